@@ -1,27 +1,51 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
-	"tg-antispam/internal/handler"
+	"tg-antispam/internal/ai"
+	"tg-antispam/internal/config"
 )
 
+// Usage:
+//
+//	./gemini_test [-config path] <message>        classify a message with the backend configured under ai_api
+//	GEMINI_API_KEY=... ./gemini_test <message>    classify a message with Gemini
+//
+// The backends' request and response handling is checked against fake servers with go test ./internal/ai.
 func main() {
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		panic("GEMINI_API_KEY not set")
-	}
+	configPath := flag.String("config", "", "path of the config file")
+	flag.Parse()
 
-	if len(os.Args) < 2 {
-		panic("Usage: ./gemini_test <message>")
+	if flag.NArg() < 1 {
+		panic("Usage: ./gemini_test [-config path] <message>")
 	}
+	message := flag.Arg(0)
 
-	message := os.Args[1]
+	var classifier ai.Classifier
+	if apiKey := os.Getenv("GEMINI_API_KEY"); apiKey != "" {
+		classifier = ai.NewGemini("", apiKey, "gemini-2.0-flash", 20*time.Second)
+	} else {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			panic(err)
+		}
+		classifier, err = ai.New(cfg.AiApi)
+		if err != nil {
+			panic(err)
+		}
+		if classifier == nil {
+			panic("no AI backend configured under ai_api and GEMINI_API_KEY not set")
+		}
+	}
 
-	got, err := handler.ClassifyWithGemini(apiKey, "gemini-2.0-flash", message)
+	got, err := classifier.Classify(context.Background(), ai.Prompt{}, message)
 	if err != nil {
 		panic(err)
 	}
-	fmt.Println(got)
+	fmt.Printf("%s: %+v\n", classifier.Name(), got)
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"tg-antispam/internal/config"
+	"tg-antispam/internal/urlresolve"
+)
+
+// Usage:
+//
+//	./urlresolve_test [-config path] <link>...    resolve links with the settings under url_resolve
+//
+// The resolver's own checks against a local redirect chain run with go test ./internal/urlresolve.
+func main() {
+	configPath := flag.String("config", "", "path of the config file")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		panic("Usage: ./urlresolve_test [-config path] <link>...")
+	}
+
+	options := urlresolve.Options{MaxHops: 5, Timeout: 5 * time.Second}
+	if *configPath != "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			panic(err)
+		}
+		options.MaxHops = cfg.UrlResolve.MaxHops
+		options.Timeout = time.Duration(cfg.UrlResolve.TimeoutSeconds) * time.Second
+	}
+	resolver := urlresolve.New(urlresolve.NewPublicClient(options.Timeout), options)
+	for _, link := range flag.Args() {
+		resolution, err := resolver.Resolve(context.Background(), link)
+		fmt.Printf("%s (truncated: %v, error: %v)\n", strings.Join(resolution.Chain, " -> "), resolution.Truncated, err)
+	}
+}
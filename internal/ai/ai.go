@@ -0,0 +1,139 @@
+// Package ai classifies messages as spam or ham with a large language model.
+// Backends talk to Gemini, any OpenAI-compatible chat endpoint or a local
+// Ollama server, and all of them ask for the same structured JSON verdict.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-antispam/internal/config"
+	"tg-antispam/internal/logger"
+)
+
+// Labels a classifier can answer with
+const (
+	LabelSpam = "spam"
+	LabelHam  = "ham"
+)
+
+// Backends selectable with ai_api.provider
+const (
+	ProviderGemini = "gemini"
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+)
+
+// Verdict is the structured answer of a classifier
+type Verdict struct {
+	Label      string  `json:"label"`
+	Confidence float64 `json:"confidence"`
+	Rationale  string  `json:"rationale"`
+}
+
+// IsSpam reports whether the message was labeled as spam
+func (v Verdict) IsSpam() bool {
+	return v.Label == LabelSpam
+}
+
+// Classifier labels a message as spam or ham
+type Classifier interface {
+	// Name returns the backend and model, e.g. "openai/gpt-4o-mini"
+	Name() string
+	// Classify asks the model for a verdict on text, judged by the group's prompt
+	Classify(ctx context.Context, prompt Prompt, text string) (Verdict, error)
+}
+
+// verdictSchema is the JSON schema of Verdict for backends that support structured output
+var verdictSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"label":      map[string]interface{}{"type": "string", "enum": []string{LabelSpam, LabelHam}},
+		"confidence": map[string]interface{}{"type": "number"},
+		"rationale":  map[string]interface{}{"type": "string"},
+	},
+	"required": []string{"label", "confidence", "rationale"},
+}
+
+// parseVerdict decodes the JSON verdict from a model answer, tolerating markdown code fences
+func parseVerdict(content string) (Verdict, error) {
+	content = strings.TrimSpace(content)
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		content = content[start : end+1]
+	}
+
+	var verdict Verdict
+	if err := json.Unmarshal([]byte(content), &verdict); err != nil {
+		return Verdict{}, fmt.Errorf("invalid verdict %q: %w", content, err)
+	}
+	verdict.Label = strings.ToLower(strings.TrimSpace(verdict.Label))
+	if verdict.Label != LabelSpam && verdict.Label != LabelHam {
+		return Verdict{}, fmt.Errorf("unknown label %q", verdict.Label)
+	}
+	verdict.Confidence = min(max(verdict.Confidence, 0), 1)
+	return verdict, nil
+}
+
+// New creates the classifier selected by cfg.Provider.
+// It returns nil without error when the selected backend is not configured.
+func New(cfg config.AiApiConfig) (Classifier, error) {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 20 * time.Second
+	}
+
+	switch cfg.Provider {
+	case "", ProviderGemini:
+		if cfg.GeminiApiKey == "" {
+			return nil, nil
+		}
+		return NewGemini(cfg.GeminiBaseURL, cfg.GeminiApiKey, cfg.GeminiModel, timeout), nil
+	case ProviderOpenAI:
+		if cfg.OpenAI.BaseURL == "" || cfg.OpenAI.Model == "" {
+			return nil, nil
+		}
+		return NewOpenAI(cfg.OpenAI.BaseURL, cfg.OpenAI.ApiKey, cfg.OpenAI.Model, timeout), nil
+	case ProviderOllama:
+		if cfg.Ollama.Model == "" {
+			return nil, nil
+		}
+		return NewOllama(cfg.Ollama.BaseURL, cfg.Ollama.Model, timeout), nil
+	}
+	return nil, fmt.Errorf("unknown ai provider %q", cfg.Provider)
+}
+
+var (
+	current   Classifier
+	currentMu sync.RWMutex
+)
+
+// Initialize creates the classifier configured under ai_api
+func Initialize(cfg config.AiApiConfig) {
+	classifier, err := New(cfg)
+	if err != nil {
+		logger.Warningf("Error initializing AI classifier: %v", err)
+	}
+
+	currentMu.Lock()
+	current = classifier
+	currentMu.Unlock()
+	SetLimits(Limits{
+		CacheTTL:         time.Duration(cfg.CacheTTLMinutes) * time.Minute,
+		GroupDailyBudget: cfg.GroupDailyBudget,
+		RatePerMinute:    cfg.RatePerMinute,
+	})
+	if classifier != nil {
+		logger.Infof("AI classifier initialized: %s", classifier.Name())
+	}
+}
+
+// Current returns the configured classifier, nil when AI checks are not configured
+func Current() Classifier {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeVerdict is what the fake servers answer with
+const fakeVerdict = `{"label": "spam", "confidence": 0.93, "rationale": "advertises a crypto giveaway"}`
+
+// fakeBackend starts a server that checks the request of a backend and answers with response
+func fakeBackend(t *testing.T, path, header string, response interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != path {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("key") != "" {
+			http.Error(w, "API key in query string", http.StatusBadRequest)
+			return
+		}
+		if header != "" && !strings.Contains(r.Header.Get(header), "test-key") {
+			http.Error(w, "missing API key header", http.StatusUnauthorized)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if request := fmt.Sprint(body); !strings.Contains(request, "free USDT") || !strings.Contains(request, "Crypto giveaways are spam.") ||
+			!strings.Contains(request, "free airdrop, DM me") || !strings.Contains(request, "BTC looks weak today") {
+			http.Error(w, "message, policy or examples missing from request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var testPrompt = Prompt{Policy: "Crypto giveaways are spam.", SpamExamples: []string{"free airdrop, DM me"}, HamExamples: []string{"BTC looks weak today"}}
+
+func TestBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		header   string // header that must carry the API key
+		response interface{}
+		newFunc  func(url string) Classifier
+	}{
+		{
+			name:   "gemini",
+			path:   "/models/test-model:generateContent",
+			header: "X-Goog-Api-Key",
+			response: map[string]interface{}{
+				"candidates": []interface{}{
+					map[string]interface{}{"content": map[string]interface{}{"parts": []interface{}{map[string]string{"text": fakeVerdict}}}},
+				},
+			},
+			newFunc: func(url string) Classifier { return NewGemini(url, "test-key", "test-model", 5*time.Second) },
+		},
+		{
+			name:   "openai",
+			path:   "/chat/completions",
+			header: "Authorization",
+			response: map[string]interface{}{
+				"choices": []interface{}{
+					map[string]interface{}{"message": map[string]string{"role": "assistant", "content": "```json\n" + fakeVerdict + "\n```"}},
+				},
+			},
+			newFunc: func(url string) Classifier { return NewOpenAI(url, "test-key", "test-model", 5*time.Second) },
+		},
+		{
+			name: "ollama",
+			path: "/api/chat",
+			response: map[string]interface{}{
+				"message": map[string]string{"role": "assistant", "content": fakeVerdict},
+				"done":    true,
+			},
+			newFunc: func(url string) Classifier { return NewOllama(url, "test-model", 5*time.Second) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeBackend(t, tt.path, tt.header, tt.response)
+			got, err := tt.newFunc(server.URL).Classify(context.Background(), testPrompt, "Join now and get free USDT!")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.IsSpam() || got.Confidence != 0.93 || got.Rationale == "" {
+				t.Errorf("unexpected verdict %+v", got)
+			}
+		})
+	}
+}
+
+func TestGeminiBlockedPromptIsNotSpam(t *testing.T) {
+	server := fakeBackend(t, "/models/test-model:generateContent", "X-Goog-Api-Key", map[string]interface{}{
+		"promptFeedback": map[string]string{"blockReason": "SAFETY"},
+	})
+	got, err := NewGemini(server.URL, "test-key", "test-model", 5*time.Second).Classify(context.Background(), testPrompt, "Join now and get free USDT!")
+	if err == nil {
+		t.Fatalf("blocked prompt classified as %+v, want an error", got)
+	}
+}
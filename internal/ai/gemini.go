@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultGeminiBaseURL is the Gemini API endpoint
+const DefaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// Gemini classifies messages with the Gemini generateContent API
+type Gemini struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewGemini creates a Gemini backend, baseURL defaults to DefaultGeminiBaseURL
+func NewGemini(baseURL, apiKey, model string, timeout time.Duration) *Gemini {
+	if baseURL == "" {
+		baseURL = DefaultGeminiBaseURL
+	}
+	return &Gemini{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *Gemini) Name() string { return ProviderGemini + "/" + g.model }
+
+func (g *Gemini) Classify(ctx context.Context, prompt Prompt, text string) (Verdict, error) {
+	body := map[string]interface{}{
+		"systemInstruction": map[string]interface{}{
+			"parts": []map[string]string{{"text": prompt.SystemPrompt()}},
+		},
+		"contents": []map[string]interface{}{
+			{"role": "user", "parts": []map[string]string{{"text": text}}},
+		},
+		"generationConfig": map[string]interface{}{
+			"responseMimeType": "application/json",
+			"responseSchema":   verdictSchema,
+			"temperature":      0,
+		},
+	}
+
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+		PromptFeedback struct {
+			BlockReason string `json:"blockReason"`
+		} `json:"promptFeedback"`
+	}
+	url := fmt.Sprintf("%s/models/%s:generateContent", g.baseURL, g.model)
+	// the key goes in a header so it doesn't end up in URLs of logged errors
+	headers := map[string]string{"x-goog-api-key": g.apiKey}
+	if err := postJSON(ctx, g.client, url, headers, body, &result); err != nil {
+		return Verdict{}, fmt.Errorf("gemini: %w", err)
+	}
+
+	if len(result.Candidates) == 0 {
+		if result.PromptFeedback.BlockReason != "" {
+			// the prompt itself was blocked by the safety filters, which says nothing about spam
+			return Verdict{}, fmt.Errorf("gemini: prompt blocked by safety filters: %s", result.PromptFeedback.BlockReason)
+		}
+		return Verdict{}, fmt.Errorf("gemini: no candidates returned")
+	}
+	if len(result.Candidates[0].Content.Parts) == 0 {
+		return Verdict{}, fmt.Errorf("gemini: no text parts returned")
+	}
+	return parseVerdict(result.Candidates[0].Content.Parts[0].Text)
+}
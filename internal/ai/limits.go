@@ -0,0 +1,205 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-antispam/internal/normalize"
+)
+
+var (
+	// ErrNotConfigured is returned when no backend is configured under ai_api
+	ErrNotConfigured = errors.New("no AI classifier configured")
+	// ErrBudgetExceeded is returned when the group used up its daily calls
+	ErrBudgetExceeded = errors.New("daily AI budget of the group exceeded")
+	// ErrRateLimited is returned when the global rate limit is reached
+	ErrRateLimited = errors.New("AI rate limit reached")
+)
+
+// maxCacheEntries bounds the result cache, expired entries are swept beyond it
+const maxCacheEntries = 10000
+
+// Limits controls the cost of AI checks
+type Limits struct {
+	CacheTTL         time.Duration // how long a verdict is reused for the same content
+	GroupDailyBudget int           // classifier calls per group and day, 0 is unlimited
+	RatePerMinute    int           // global calls per minute, 0 is unlimited
+}
+
+// GroupUsage counts the AI checks of a group on the current day
+type GroupUsage struct {
+	GroupID      int64
+	Calls        int // requests sent to the backend
+	CacheHits    int // checks answered from the cache
+	BudgetDenied int // checks skipped because the budget was used up
+	RateLimited  int // checks skipped because of the global rate limit
+}
+
+type cacheEntry struct {
+	verdict Verdict
+	expires time.Time
+}
+
+// tokenBucket is a token bucket refilled continuously, its capacity is one minute worth of calls
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+var (
+	limits   Limits
+	limitsMu sync.Mutex
+	bucket   *tokenBucket
+	cache    = make(map[string]cacheEntry)
+	usageDay string
+	usage    = make(map[int64]*GroupUsage)
+)
+
+// SetLimits replaces the cost limits and clears the result cache
+func SetLimits(l Limits) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	limits = l
+	bucket = nil
+	if l.RatePerMinute > 0 {
+		capacity := float64(l.RatePerMinute)
+		bucket = &tokenBucket{tokens: capacity, capacity: capacity, rate: capacity / 60, last: time.Now()}
+	}
+	cache = make(map[string]cacheEntry)
+}
+
+// contentKey hashes the group, its policy and the normalized text so that trivially altered copies share a cache entry.
+// Verdicts are kept per group, the prompt's examples come from the group's own history.
+func contentKey(groupID int64, policy, text string) string {
+	folded := strings.Join(strings.Fields(strings.ToLower(normalize.Skeleton(text))), " ")
+	sum := sha256.Sum256([]byte(strconv.FormatInt(groupID, 10) + "\x00" + strings.TrimSpace(policy) + "\x00" + folded))
+	return hex.EncodeToString(sum[:])
+}
+
+// groupUsage returns the usage of the group for today, the caller holds the lock
+func groupUsage(groupID int64, now time.Time) *GroupUsage {
+	if day := now.Format("2006-01-02"); day != usageDay {
+		usageDay = day
+		usage = make(map[int64]*GroupUsage)
+	}
+	u, ok := usage[groupID]
+	if !ok {
+		u = &GroupUsage{GroupID: groupID}
+		usage[groupID] = u
+	}
+	return u
+}
+
+// Classify checks text for a group with the configured classifier. The prompt is only built on a cache miss.
+// Verdicts are cached by group and content, and calls are limited by the group's daily budget and the global rate.
+func Classify(ctx context.Context, groupID int64, policy string, buildPrompt func() Prompt, text string) (Verdict, error) {
+	classifier := Current()
+	if classifier == nil {
+		return Verdict{}, ErrNotConfigured
+	}
+
+	key := contentKey(groupID, policy, text)
+	now := time.Now()
+
+	limitsMu.Lock()
+	u := groupUsage(groupID, now)
+	if entry, ok := cache[key]; ok && now.Before(entry.expires) {
+		u.CacheHits++
+		limitsMu.Unlock()
+		return entry.verdict, nil
+	}
+	if limits.GroupDailyBudget > 0 && u.Calls >= limits.GroupDailyBudget {
+		u.BudgetDenied++
+		limitsMu.Unlock()
+		return Verdict{}, ErrBudgetExceeded
+	}
+	if bucket != nil && !bucket.take(now) {
+		u.RateLimited++
+		limitsMu.Unlock()
+		return Verdict{}, ErrRateLimited
+	}
+	u.Calls++
+	limitsMu.Unlock()
+
+	verdict, err := classifier.Classify(ctx, buildPrompt(), text)
+	if err != nil || limits.CacheTTL <= 0 {
+		return verdict, err
+	}
+
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	if len(cache) >= maxCacheEntries {
+		sweepCache(now)
+	}
+	cache[key] = cacheEntry{verdict: verdict, expires: now.Add(limits.CacheTTL)}
+	return verdict, nil
+}
+
+// sweepCache drops expired entries, and arbitrary ones if the cache is still full. The caller holds the lock.
+func sweepCache(now time.Time) {
+	for key, entry := range cache {
+		if !now.Before(entry.expires) {
+			delete(cache, key)
+		}
+	}
+	for key := range cache {
+		if len(cache) < maxCacheEntries/2 {
+			break
+		}
+		delete(cache, key)
+	}
+}
+
+// Usage returns today's AI check usage of a group
+func Usage(groupID int64) GroupUsage {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	return *groupUsage(groupID, time.Now())
+}
+
+// UsageReport returns today's usage of all groups, most calls first, and the totals
+func UsageReport() (groups []GroupUsage, total GroupUsage) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	groupUsage(0, time.Now())
+	for _, u := range usage {
+		if u.Calls+u.CacheHits+u.BudgetDenied+u.RateLimited == 0 {
+			continue
+		}
+		groups = append(groups, *u)
+		total.Calls += u.Calls
+		total.CacheHits += u.CacheHits
+		total.BudgetDenied += u.BudgetDenied
+		total.RateLimited += u.RateLimited
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Calls > groups[j].Calls })
+	return groups, total
+}
+
+// GroupDailyBudget returns the configured calls per group and day, 0 is unlimited
+func GroupDailyBudget() int {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	return limits.GroupDailyBudget
+}
@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOllamaBaseURL is the address of a local Ollama server
+const DefaultOllamaBaseURL = "http://localhost:11434"
+
+// Ollama classifies messages with a local model served by Ollama
+type Ollama struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewOllama creates an Ollama backend, baseURL defaults to DefaultOllamaBaseURL
+func NewOllama(baseURL, model string, timeout time.Duration) *Ollama {
+	if baseURL == "" {
+		baseURL = DefaultOllamaBaseURL
+	}
+	return &Ollama{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (o *Ollama) Name() string { return ProviderOllama + "/" + o.model }
+
+func (o *Ollama) Classify(ctx context.Context, prompt Prompt, text string) (Verdict, error) {
+	body := map[string]interface{}{
+		"model": o.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": prompt.SystemPrompt()},
+			{"role": "user", "content": text},
+		},
+		"format":  verdictSchema,
+		"stream":  false,
+		"options": map[string]interface{}{"temperature": 0},
+	}
+
+	var result struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+	if err := postJSON(ctx, o.client, o.baseURL+"/api/chat", nil, body, &result); err != nil {
+		return Verdict{}, fmt.Errorf("ollama: %w", err)
+	}
+	return parseVerdict(result.Message.Content)
+}
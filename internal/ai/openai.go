@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAI classifies messages with an OpenAI-compatible chat completions endpoint
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAI creates an OpenAI-compatible backend, baseURL is e.g. "https://api.openai.com/v1"
+func NewOpenAI(baseURL, apiKey, model string, timeout time.Duration) *OpenAI {
+	return &OpenAI{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (o *OpenAI) Name() string { return ProviderOpenAI + "/" + o.model }
+
+func (o *OpenAI) Classify(ctx context.Context, prompt Prompt, text string) (Verdict, error) {
+	body := map[string]interface{}{
+		"model": o.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": prompt.SystemPrompt()},
+			{"role": "user", "content": text},
+		},
+		"response_format": map[string]string{"type": "json_object"},
+		"temperature":     0,
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	headers := map[string]string{}
+	if o.apiKey != "" {
+		headers["Authorization"] = "Bearer " + o.apiKey
+	}
+	if err := postJSON(ctx, o.client, o.baseURL+"/chat/completions", headers, body, &result); err != nil {
+		return Verdict{}, fmt.Errorf("openai: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return Verdict{}, fmt.Errorf("openai: no choices returned")
+	}
+	return parseVerdict(result.Choices[0].Message.Content)
+}
@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPolicy describes spam for groups without a policy of their own
+const DefaultPolicy = "Spam is advertising, scams, phishing, pornography, gambling, drugs, crypto or investment schemes, " +
+	"and harassment. Ordinary chat in any language is ham."
+
+// maxExampleLength bounds the runes of each example in the prompt
+const maxExampleLength = 300
+
+// Prompt is what a group tells the model about its idea of spam
+type Prompt struct {
+	Policy       string   // the group's own description of spam, DefaultPolicy when empty
+	SpamExamples []string // recent messages confirmed as spam in the group
+	HamExamples  []string // recent messages confirmed as ham in the group
+	Profile      bool     // the text describes a joining account instead of a message
+}
+
+// SystemPrompt builds the instructions for the model: the policy, the group's examples and the answer format
+func (p Prompt) SystemPrompt() string {
+	policy := strings.TrimSpace(p.Policy)
+	if policy == "" {
+		policy = DefaultPolicy
+	}
+
+	var sb strings.Builder
+	if p.Profile {
+		sb.WriteString("You moderate a Telegram group. A new member is joining, decide from the name, username and bio " +
+			"whether the account exists to send spam according to the group's policy. Most accounts are ordinary people, " +
+			"only answer spam when the profile itself advertises or solicits.\n\n")
+	} else {
+		sb.WriteString("You moderate a Telegram group. Decide whether the user message is spam according to the group's policy.\n\n")
+	}
+	sb.WriteString("Group policy:\n")
+	sb.WriteString(policy)
+	writeExamples(&sb, "Recent messages this group confirmed as spam:", p.SpamExamples)
+	writeExamples(&sb, "Recent messages this group confirmed as acceptable:", p.HamExamples)
+	sb.WriteString("\n\nAnswer with a JSON object only: " +
+		`{"label": "spam" or "ham", "confidence": a number from 0 to 1, "rationale": one short sentence}.`)
+	return sb.String()
+}
+
+func writeExamples(sb *strings.Builder, title string, examples []string) {
+	if len(examples) == 0 {
+		return
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(title)
+	for _, example := range examples {
+		if runes := []rune(example); len(runes) > maxExampleLength {
+			example = string(runes[:maxExampleLength]) + "…"
+		}
+		sb.WriteString(fmt.Sprintf("\n- %q", example))
+	}
+}
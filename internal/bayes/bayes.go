@@ -0,0 +1,128 @@
+// Package bayes implements a naive Bayes text classifier that tells spam
+// from ham. It learns incrementally, one message at a time, and counts each
+// token once per message.
+package bayes
+
+import (
+	"math"
+	"sync"
+)
+
+// DocsToken is the reserved token under which the number of learned messages is stored
+const DocsToken = "#docs"
+
+// MinDocs is the number of spam and ham messages each needed before the classifier gives an answer
+const MinDocs = 5
+
+// Counts is the number of spam and ham messages a token was seen in
+type Counts struct {
+	Spam int
+	Ham  int
+}
+
+// Classifier is a naive Bayes spam classifier, safe for concurrent use
+type Classifier struct {
+	mu     sync.RWMutex
+	docs   Counts
+	tokens map[string]Counts
+}
+
+// New creates an empty classifier
+func New() *Classifier {
+	return &Classifier{tokens: make(map[string]Counts)}
+}
+
+// Set restores the counts of a token, DocsToken restores the number of learned messages
+func (c *Classifier) Set(token string, counts Counts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if token == DocsToken {
+		c.docs = counts
+		return
+	}
+	c.tokens[token] = counts
+}
+
+// Learn adds a message with the given label and returns the updated counts of the changed tokens,
+// including DocsToken, so that they can be persisted
+func (c *Classifier) Learn(text string, spam bool) map[string]Counts {
+	return c.update(Tokenize(text), spam, 1)
+}
+
+// Unlearn removes a message previously learned with the given label
+func (c *Classifier) Unlearn(text string, spam bool) map[string]Counts {
+	return c.update(Tokenize(text), spam, -1)
+}
+
+func (c *Classifier) update(tokens []string, spam bool, delta int) map[string]Counts {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changed := make(map[string]Counts, len(tokens)+1)
+	c.docs = addCounts(c.docs, spam, delta)
+	changed[DocsToken] = c.docs
+	for _, token := range tokens {
+		counts := addCounts(c.tokens[token], spam, delta)
+		if counts.Spam == 0 && counts.Ham == 0 {
+			delete(c.tokens, token)
+		} else {
+			c.tokens[token] = counts
+		}
+		changed[token] = counts
+	}
+	return changed
+}
+
+// addCounts adds delta to the spam or ham count, never going below zero
+func addCounts(counts Counts, spam bool, delta int) Counts {
+	if spam {
+		counts.Spam = max(counts.Spam+delta, 0)
+	} else {
+		counts.Ham = max(counts.Ham+delta, 0)
+	}
+	return counts
+}
+
+// SpamProbability returns the probability that text is spam.
+// ok is false while the classifier has not learned enough messages or knows none of the tokens.
+func (c *Classifier) SpamProbability(text string) (probability float64, ok bool) {
+	tokens := Tokenize(text)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.docs.Spam < MinDocs || c.docs.Ham < MinDocs {
+		return 0, false
+	}
+
+	// log odds of the prior plus the likelihood ratio of every known token, with add-one smoothing
+	spamDocs, hamDocs := float64(c.docs.Spam), float64(c.docs.Ham)
+	logOdds := math.Log(spamDocs / hamDocs)
+	known := 0
+	for _, token := range tokens {
+		counts, found := c.tokens[token]
+		if !found {
+			continue
+		}
+		known++
+		pSpam := (float64(counts.Spam) + 1) / (spamDocs + 2)
+		pHam := (float64(counts.Ham) + 1) / (hamDocs + 2)
+		logOdds += math.Log(pSpam / pHam)
+	}
+	if known == 0 {
+		return 0, false
+	}
+	return 1 / (1 + math.Exp(-logOdds)), true
+}
+
+// Stats returns the number of learned spam and ham messages and the vocabulary size
+func (c *Classifier) Stats() (spam, ham, vocabulary int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.docs.Spam, c.docs.Ham, len(c.tokens)
+}
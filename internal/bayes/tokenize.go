@@ -0,0 +1,101 @@
+package bayes
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"tg-antispam/internal/normalize"
+)
+
+var (
+	urlRegex     = regexp.MustCompile(`(?:https?://|\bt\.me/|\btelegram\.me/)([a-z0-9.-]+)`)
+	mentionRegex = regexp.MustCompile(`@[a-z0-9_]{3,32}`)
+)
+
+const (
+	minWordLength = 2
+	maxWordLength = 24
+)
+
+// Tokenize splits text into the distinct tokens used by the classifier.
+// Latin words are kept whole, runs of CJK characters have no spaces between words
+// and are split into character unigrams and bigrams. Links become "url:<host>",
+// mentions are kept with their "@" and numbers are folded into "#num".
+func Tokenize(text string) []string {
+	text = strings.ToLower(normalize.Skeleton(text))
+
+	seen := make(map[string]bool)
+	var tokens []string
+	add := func(token string) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, match := range urlRegex.FindAllStringSubmatch(text, -1) {
+		host := strings.TrimPrefix(match[1], "www.")
+		if strings.HasPrefix(match[0], "t.me") || strings.HasPrefix(match[0], "telegram.me") {
+			host = "t.me"
+		}
+		add("url:" + host)
+	}
+	text = urlRegex.ReplaceAllString(text, " ")
+	for _, mention := range mentionRegex.FindAllString(text, -1) {
+		add(mention)
+	}
+	text = mentionRegex.ReplaceAllString(text, " ")
+
+	var word, cjk []rune
+	flushWord := func() {
+		if len(word) >= minWordLength && len(word) <= maxWordLength {
+			if isNumber(word) {
+				add("#num")
+			} else {
+				add(string(word))
+			}
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		for i := range cjk {
+			add(string(cjk[i]))
+			if i+1 < len(cjk) {
+				add(string(cjk[i : i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+// isCJK reports whether r belongs to a script written without spaces between words
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai)
+}
+
+func isNumber(word []rune) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
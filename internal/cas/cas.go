@@ -0,0 +1,73 @@
+package cas
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"tg-antispam/internal/config"
+	"tg-antispam/internal/crash"
+	"tg-antispam/internal/logger"
+)
+
+var (
+	mirror       *Mirror
+	client       *Client
+	liveFallback = true
+)
+
+// Initialize sets up the mirror and the live API client and starts refreshing the export
+func Initialize(cfg config.CasConfig) {
+	liveFallback = cfg.LiveFallback
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	client = NewClient(cfg.ApiURL, &http.Client{Timeout: timeout})
+
+	if cfg.ExportSource == "" {
+		logger.Info("CAS export mirror is disabled, using the live API")
+		return
+	}
+	mirror = NewMirror(cfg.ExportSource, &http.Client{Timeout: 2 * time.Minute})
+
+	interval := time.Duration(cfg.RefreshMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	crash.SafeGoroutine("cas-export-refresh", func() {
+		for {
+			refresh()
+			time.Sleep(interval)
+		}
+	})
+}
+
+// refresh reloads the export, keeping the previous IDs on failure
+func refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := mirror.Load(ctx); err != nil {
+		logger.Warningf("Error loading CAS export: %v", err)
+		return
+	}
+	logger.Infof("Loaded %d user IDs from the CAS export", mirror.Len())
+}
+
+// MirrorLoaded reports whether lookups are answered by the in-memory mirror rather than the live API
+func MirrorLoaded() bool {
+	return mirror != nil && mirror.Loaded()
+}
+
+// Check reports whether a user is listed in CAS. The mirror is authoritative once loaded;
+// before that, or without a mirror, the live API is queried if the fallback is enabled.
+func Check(ctx context.Context, userID int64) (bool, error) {
+	if MirrorLoaded() {
+		return mirror.Contains(userID), nil
+	}
+	if !liveFallback || client == nil {
+		return false, nil
+	}
+	return client.Check(ctx, userID)
+}
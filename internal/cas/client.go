@@ -0,0 +1,53 @@
+package cas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Client queries the live CAS API
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a client for the API at baseURL, e.g. "https://api.cas.chat"
+func NewClient(baseURL string, client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+// Check reports whether a user is listed in CAS
+func (c *Client) Check(ctx context.Context, userID int64) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/check?user_id="+strconv.FormatInt(userID, 10), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("cas api returned status code %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Ok     bool `json:"ok"`
+		Result struct {
+			Offenses  int   `json:"offenses"`
+			TimeAdded int64 `json:"time_added"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Ok, nil
+}
@@ -0,0 +1,141 @@
+// Package cas checks users against the Combot Anti-Spam System (CAS).
+// The bulk export is mirrored into memory so that join checks are local lookups,
+// the live API is only used while no export has been loaded.
+package cas
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mirror is an in-memory copy of the CAS bulk export, a CSV whose first column is the banned user ID
+type Mirror struct {
+	source string // URL or local file path of the export
+	client *http.Client
+
+	ids       []int64 // sorted banned user IDs
+	updatedAt time.Time
+	mu        sync.RWMutex
+}
+
+// NewMirror creates a mirror of the export at source; client may be nil to use http.DefaultClient
+func NewMirror(source string, client *http.Client) *Mirror {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Mirror{source: source, client: client}
+}
+
+// Load fetches and parses the export, replacing the mirrored IDs.
+// The previous IDs are kept if the export cannot be read or is empty.
+func (m *Mirror) Load(ctx context.Context) error {
+	body, err := m.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	ids, err := parseExport(body)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("cas export %s contains no user IDs", m.source)
+	}
+
+	m.mu.Lock()
+	m.ids = ids
+	m.updatedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// open returns the export body from a URL or a local file
+func (m *Mirror) open(ctx context.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(m.source, "http://") && !strings.HasPrefix(m.source, "https://") {
+		return os.Open(m.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("cas export returned status code %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// parseExport reads the user IDs of an export, skipping the header and malformed lines
+func parseExport(r io.Reader) ([]int64, error) {
+	var ids []int64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		field, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ",")
+		id, err := strconv.ParseInt(strings.Trim(field, `"`), 10, 64)
+		if err != nil || id <= 0 {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	// drop duplicates in place
+	unique := ids[:0]
+	for i, id := range ids {
+		if i == 0 || id != ids[i-1] {
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// Contains reports whether the user is in the mirrored export
+func (m *Mirror) Contains(userID int64) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	i := sort.Search(len(m.ids), func(i int) bool { return m.ids[i] >= userID })
+	return i < len(m.ids) && m.ids[i] == userID
+}
+
+// Loaded reports whether an export has been loaded
+func (m *Mirror) Loaded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return !m.updatedAt.IsZero()
+}
+
+// Len returns the number of mirrored user IDs
+func (m *Mirror) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.ids)
+}
+
+// UpdatedAt returns when the export was last loaded successfully
+func (m *Mirror) UpdatedAt() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.updatedAt
+}
@@ -0,0 +1,114 @@
+package cas
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+const export = `user_id,offenses,time_added
+1001,3,2024-01-01T00:00:00.000Z
+"1002",1,2024-01-02T00:00:00.000Z
+1001,3,2024-01-01T00:00:00.000Z
+not an id,1,2024-01-03T00:00:00.000Z
+-5,1,2024-01-04T00:00:00.000Z
+
+1003
+`
+
+// checkIDs fails the test unless the mirror holds exactly the IDs of export
+func checkIDs(t *testing.T, m *Mirror) {
+	t.Helper()
+	if m.Len() != 3 || !m.Loaded() {
+		t.Fatalf("mirrored %d IDs (loaded %v), want 3", m.Len(), m.Loaded())
+	}
+	for _, id := range []int64{1001, 1002, 1003} {
+		if !m.Contains(id) {
+			t.Errorf("user %d missing from the mirror", id)
+		}
+	}
+	for _, id := range []int64{1000, 1004, 5} {
+		if m.Contains(id) {
+			t.Errorf("user %d should not be in the mirror", id)
+		}
+	}
+}
+
+func TestMirrorLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv")
+	if err := os.WriteFile(path, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMirror(path, nil)
+	if err := m.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	checkIDs(t, m)
+}
+
+func TestMirrorLoadHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(export))
+	}))
+	defer server.Close()
+
+	m := NewMirror(server.URL+"/export.csv", server.Client())
+	if err := m.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	checkIDs(t, m)
+}
+
+func TestMirrorMalformedExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>maintenance</html>\nuser_id\n"))
+	}))
+	defer server.Close()
+
+	m := NewMirror(server.URL, server.Client())
+	if err := m.Load(context.Background()); err == nil {
+		t.Fatal("loaded an export without user IDs")
+	}
+	if m.Loaded() || m.Len() != 0 {
+		t.Errorf("mirror loaded %d IDs from a malformed export", m.Len())
+	}
+}
+
+func TestMirrorRefreshFailureKeepsSnapshot(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(export))
+	}))
+	defer server.Close()
+
+	m := NewMirror(server.URL, server.Client())
+	if err := m.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	updatedAt := m.UpdatedAt()
+
+	failing.Store(true)
+	if err := m.Load(context.Background()); err == nil {
+		t.Fatal("refresh succeeded against a failing server")
+	}
+	checkIDs(t, m)
+	if !m.UpdatedAt().Equal(updatedAt) {
+		t.Errorf("failed refresh changed the update time")
+	}
+}
+
+func TestMirrorMissingFile(t *testing.T) {
+	m := NewMirror(filepath.Join(t.TempDir(), "missing.csv"), nil)
+	if err := m.Load(context.Background()); err == nil || m.Loaded() {
+		t.Errorf("loading a missing file: %v, loaded %v", err, m.Loaded())
+	}
+}
@@ -14,6 +14,11 @@ type Config struct {
 	Antispam AntispamConfig `mapstructure:"antispam"`
 	Database DatabaseConfig `mapstructure:"database"`
 	AiApi    AiApiConfig    `mapstructure:"ai_api"`
+	Cas      CasConfig      `mapstructure:"cas"`
+
+	UrlResolve UrlResolveConfig `mapstructure:"url_resolve"`
+
+	BlocklistProviders []BlocklistProviderConfig `mapstructure:"blocklist_providers"`
 }
 
 // Telegram bot configuration
@@ -63,16 +68,105 @@ type DatabaseConfig struct {
 
 // anti-spam feature settings
 type AntispamConfig struct {
-	BanRandomUsername bool `mapstructure:"ban_random_username"`
-	BanEmojiName      bool `mapstructure:"ban_emoji_name"`
-	BanBioLink        bool `mapstructure:"ban_bio_link"`
-	UseCAS            bool `mapstructure:"use_cas"`
-	BanPremium        bool `mapstructure:"ban_premium"`
+	BanRandomUsername       bool           `mapstructure:"ban_random_username"`
+	BanEmojiName            bool           `mapstructure:"ban_emoji_name"`
+	BanBioLink              bool           `mapstructure:"ban_bio_link"`
+	UseCAS                  bool           `mapstructure:"use_cas"`
+	BanPremium              bool           `mapstructure:"ban_premium"`
+	RestrictScore           int            `mapstructure:"restrict_score"`
+	BanScore                int            `mapstructure:"ban_score"`
+	EmojiMinCount           int            `mapstructure:"emoji_min_count"`
+	EmojiRatio              int            `mapstructure:"emoji_ratio"`
+	RandomUsernameThreshold int            `mapstructure:"random_username_threshold"`
+	MinAccountAgeDays       int            `mapstructure:"min_account_age_days"`
+	AccountAgeAction        string         `mapstructure:"account_age_action"`
+	BanAvatarHash           bool           `mapstructure:"ban_avatar_hash"`
+	BanNoAvatar             bool           `mapstructure:"ban_no_avatar"`
+	ShareAvatarHashes       bool           `mapstructure:"share_avatar_hashes"`
+	BanImpersonation        bool           `mapstructure:"ban_impersonation"`
+	BanInvisibleChars       bool           `mapstructure:"ban_invisible_chars"`
+	DefaultProviders        string         `mapstructure:"default_blocklist_providers"`
+	EnableMessageScan       bool           `mapstructure:"enable_message_scan"`
+	MessageProviderCheck    bool           `mapstructure:"message_provider_check"`
+	EnableAiCheck           bool           `mapstructure:"enable_ai_check"`
+	EnableAiProfile         bool           `mapstructure:"enable_ai_profile"`
+	MessageAction           string         `mapstructure:"message_action"`
+	EnableBayes             bool           `mapstructure:"enable_bayes"`
+	FloodMessages           int            `mapstructure:"flood_messages"`
+	FloodMedia              int            `mapstructure:"flood_media"`
+	FloodMentions           int            `mapstructure:"flood_mentions"`
+	FloodWindowSec          int            `mapstructure:"flood_window_seconds"`
+	FloodAction             string         `mapstructure:"flood_action"`
+	FloodMuteMinutes        int            `mapstructure:"flood_mute_minutes"`
+	DuplicateGroups         int            `mapstructure:"duplicate_groups"`
+	DuplicateUsers          int            `mapstructure:"duplicate_users"`
+	DuplicateWindowMinutes  int            `mapstructure:"duplicate_window_minutes"`
+	EnableEditScan          bool           `mapstructure:"enable_edit_scan"`
+	EditWindowMinutes       int            `mapstructure:"edit_window_minutes"`
+	BlockInlineBots         bool           `mapstructure:"block_inline_bots"`
+	ForwardChannelPolicy    string         `mapstructure:"forward_channel_policy"`
+	ForwardChannelAction    string         `mapstructure:"forward_channel_action"`
+	BlockHiddenForwards     bool           `mapstructure:"block_hidden_forwards"`
+	HiddenForwardAction     string         `mapstructure:"hidden_forward_action"`
+	RuleScores              map[string]int `mapstructure:"rule_scores"`
+}
+
+// CAS (Combot Anti-Spam) mirror and API settings
+type CasConfig struct {
+	ExportSource   string `mapstructure:"export_source"`
+	RefreshMinutes int    `mapstructure:"refresh_minutes"`
+	LiveFallback   bool   `mapstructure:"live_fallback"`
+	ApiURL         string `mapstructure:"api_url"`
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"`
+}
+
+// following the redirects of links in group messages
+type UrlResolveConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	MaxHops         int  `mapstructure:"max_hops"`
+	TimeoutSeconds  int  `mapstructure:"timeout_seconds"`
+	CacheTTLMinutes int  `mapstructure:"cache_ttl_minutes"`
+	MaxURLs         int  `mapstructure:"max_urls"`
+}
+
+// external or local blocklist a joining user is checked against
+type BlocklistProviderConfig struct {
+	Name               string `mapstructure:"name"`
+	Type               string `mapstructure:"type"` // cas, http_json, file or ban_database
+	URL                string `mapstructure:"url"`
+	Field              string `mapstructure:"field"`
+	Path               string `mapstructure:"path"`
+	TimeoutSeconds     int    `mapstructure:"timeout_seconds"`
+	PositiveTTLMinutes int    `mapstructure:"positive_ttl_minutes"`
+	NegativeTTLMinutes int    `mapstructure:"negative_ttl_minutes"`
+	FailureThreshold   int    `mapstructure:"failure_threshold"`
+	CooldownSeconds    int    `mapstructure:"cooldown_seconds"`
 }
 
 type AiApiConfig struct {
-	GeminiApiKey string `mapstructure:"gemini_api_key"`
-	GeminiModel  string `mapstructure:"gemini_model"`
+	Provider         string       `mapstructure:"provider"`
+	TimeoutSeconds   int          `mapstructure:"timeout_seconds"`
+	CacheTTLMinutes  int          `mapstructure:"cache_ttl_minutes"`
+	GroupDailyBudget int          `mapstructure:"group_daily_budget"`
+	RatePerMinute    int          `mapstructure:"rate_per_minute"`
+	GeminiApiKey     string       `mapstructure:"gemini_api_key"`
+	GeminiModel      string       `mapstructure:"gemini_model"`
+	GeminiBaseURL    string       `mapstructure:"gemini_base_url"`
+	OpenAI           OpenAIConfig `mapstructure:"openai"`
+	Ollama           OllamaConfig `mapstructure:"ollama"`
+}
+
+// OpenAI-compatible chat completions endpoint
+type OpenAIConfig struct {
+	BaseURL string `mapstructure:"base_url"`
+	ApiKey  string `mapstructure:"api_key"`
+	Model   string `mapstructure:"model"`
+}
+
+// local Ollama server
+type OllamaConfig struct {
+	BaseURL string `mapstructure:"base_url"`
+	Model   string `mapstructure:"model"`
 }
 
 var cfg *Config
@@ -136,6 +230,62 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("antispam.ban_bio_link", true)
 	v.SetDefault("antispam.use_cas", true)
 	v.SetDefault("antispam.ban_premium", true)
+	v.SetDefault("antispam.restrict_score", 30)
+	v.SetDefault("antispam.ban_score", 100)
+	v.SetDefault("antispam.emoji_min_count", 2)
+	v.SetDefault("antispam.emoji_ratio", 0)
+	v.SetDefault("antispam.random_username_threshold", 70)
+	v.SetDefault("antispam.min_account_age_days", 0)
+	v.SetDefault("antispam.account_age_action", "restrict")
+	v.SetDefault("antispam.ban_avatar_hash", true)
+	v.SetDefault("antispam.ban_no_avatar", false)
+	v.SetDefault("antispam.share_avatar_hashes", false)
+	v.SetDefault("antispam.ban_impersonation", true)
+	v.SetDefault("antispam.ban_invisible_chars", true)
+	v.SetDefault("antispam.default_blocklist_providers", "")
+	v.SetDefault("antispam.enable_message_scan", true)
+	v.SetDefault("antispam.message_provider_check", true)
+	v.SetDefault("antispam.enable_ai_check", false)
+	v.SetDefault("antispam.enable_ai_profile", false)
+	v.SetDefault("antispam.message_action", "restrict")
+	v.SetDefault("antispam.enable_bayes", true)
+	v.SetDefault("antispam.flood_messages", 0)
+	v.SetDefault("antispam.flood_media", 0)
+	v.SetDefault("antispam.flood_mentions", 0)
+	v.SetDefault("antispam.flood_window_seconds", 10)
+	v.SetDefault("antispam.flood_action", "mute")
+	v.SetDefault("antispam.flood_mute_minutes", 10)
+	v.SetDefault("antispam.duplicate_groups", 0)
+	v.SetDefault("antispam.duplicate_users", 0)
+	v.SetDefault("antispam.duplicate_window_minutes", 10)
+	v.SetDefault("antispam.enable_edit_scan", true)
+	v.SetDefault("antispam.edit_window_minutes", 0)
+	v.SetDefault("antispam.block_inline_bots", false)
+	v.SetDefault("antispam.forward_channel_policy", "allow")
+	v.SetDefault("antispam.forward_channel_action", "delete")
+	v.SetDefault("antispam.block_hidden_forwards", false)
+	v.SetDefault("antispam.hidden_forward_action", "delete")
+	v.SetDefault("cas.export_source", "https://api.cas.chat/export.csv")
+	v.SetDefault("cas.refresh_minutes", 60)
+	v.SetDefault("cas.live_fallback", true)
+	v.SetDefault("cas.api_url", "https://api.cas.chat")
+	v.SetDefault("cas.timeout_seconds", 5)
+	v.SetDefault("url_resolve.enabled", true)
+	v.SetDefault("url_resolve.max_hops", 5)
+	v.SetDefault("url_resolve.timeout_seconds", 5)
+	v.SetDefault("url_resolve.cache_ttl_minutes", 360)
+	v.SetDefault("url_resolve.max_urls", 3)
+	v.SetDefault("ai_api.provider", "gemini")
+	v.SetDefault("ai_api.timeout_seconds", 20)
+	v.SetDefault("ai_api.cache_ttl_minutes", 1440)
+	v.SetDefault("ai_api.group_daily_budget", 200)
+	v.SetDefault("ai_api.rate_per_minute", 30)
 	v.SetDefault("ai_api.gemini_api_key", "")
 	v.SetDefault("ai_api.gemini_model", "gemini-2.0-flash")
+	v.SetDefault("ai_api.gemini_base_url", "https://generativelanguage.googleapis.com/v1beta")
+	v.SetDefault("ai_api.openai.base_url", "https://api.openai.com/v1")
+	v.SetDefault("ai_api.openai.api_key", "")
+	v.SetDefault("ai_api.openai.model", "")
+	v.SetDefault("ai_api.ollama.base_url", "http://localhost:11434")
+	v.SetDefault("ai_api.ollama.model", "")
 }
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/ai"
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// maxAiPolicyLength bounds the runes of a group's AI policy, it is sent with every AI check
+const maxAiPolicyLength = 1000
+
+// pendingAiPolicies keeps the /ai_policy argument of a user until a group is selected
+var (
+	pendingAiPolicies   = make(map[int64]string)
+	pendingAiPoliciesMu sync.Mutex
+)
+
+// handleAiPolicyCommand handles /ai_policy, which describes what the group counts as spam for the AI check
+//
+//	/ai_policy <text>   set the group's policy
+//	/ai_policy clear    go back to the default policy
+//	/ai_policy          show the group's policy
+func handleAiPolicyCommand(bot *telego.Bot, message telego.Message, policy string) error {
+	if utf8.RuneCountInString(policy) > maxAiPolicyLength {
+		language := GetBotLang(bot, message)
+		return sendHTMLReply(bot, message.Chat.ID, fmt.Sprintf(models.GetTranslation(language, "ai_policy_too_long"), maxAiPolicyLength))
+	}
+
+	pendingAiPoliciesMu.Lock()
+	pendingAiPolicies[message.From.ID] = policy
+	pendingAiPoliciesMu.Unlock()
+
+	return handleToggleCommand(bot, message, "ai_policy")
+}
+
+// executeAiPolicyCommand runs the pending /ai_policy command of the user against the selected group
+func executeAiPolicyCommand(bot *telego.Bot, query telego.CallbackQuery, groupInfo *models.GroupInfo, language string) error {
+	pendingAiPoliciesMu.Lock()
+	policy := pendingAiPolicies[query.From.ID]
+	delete(pendingAiPolicies, query.From.ID)
+	pendingAiPoliciesMu.Unlock()
+
+	message, ok := query.Message.(*telego.Message)
+	if !ok {
+		logger.Warningf("Unexpected message type in ai_policy command: %T", query.Message)
+		return nil
+	}
+
+	var text string
+	switch policy {
+	case "":
+		current := groupInfo.AiPolicy
+		if current == "" {
+			current = ai.DefaultPolicy + "\n\n" + models.GetTranslation(language, "ai_policy_default")
+		}
+		text = fmt.Sprintf(models.GetTranslation(language, "ai_policy_current"), html.EscapeString(current)) +
+			"\n\n" + models.GetTranslation(language, "ai_policy_usage")
+	case "clear":
+		groupInfo.AiPolicy = ""
+		service.UpdateGroupInfo(groupInfo)
+		text = models.GetTranslation(language, "ai_policy_cleared")
+	default:
+		groupInfo.AiPolicy = policy
+		service.UpdateGroupInfo(groupInfo)
+		text = fmt.Sprintf(models.GetTranslation(language, "ai_policy_set"), html.EscapeString(policy))
+	}
+	logger.Infof("ai_policy in group %d by %d: %q", groupInfo.GroupID, query.From.ID, policy)
+
+	return sendHTMLReply(bot, message.Chat.ID, text)
+}
+
+// commandArgument returns the raw text after the command word, keeping its line breaks
+func commandArgument(command string, name string) string {
+	return strings.TrimSpace(strings.TrimPrefix(command, name))
+}
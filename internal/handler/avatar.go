@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+)
+
+// blockUserAvatar adds the user's current profile photo to the spam avatar list of a group, 0 for all groups
+func blockUserAvatar(bot *telego.Bot, groupID int64, userID int64, addedBy string) (bool, error) {
+	hash, hasPhoto, err := rules.FetchAvatarHash(bot, userID)
+	if err != nil || !hasPhoto {
+		return false, err
+	}
+	err = saveAvatarHash(groupID, userID, hash, addedBy)
+	return err == nil, err
+}
+
+// saveAvatarHash adds an avatar hash to the spam avatar list of a group, 0 for all groups
+func saveAvatarHash(groupID int64, userID int64, hash uint64, addedBy string) error {
+	logger.Infof("Blocking avatar of user %d in group %d: %016x, added by %s", userID, groupID, hash, addedBy)
+	return service.AddAvatarHash(&models.AvatarHash{
+		GroupID:      groupID,
+		Hash:         hash,
+		SourceUserID: userID,
+		AddedBy:      addedBy,
+	})
+}
+
+// learnConfirmedAvatar adds the avatar of a user an admin confirmed as spammer to the group's spam avatar list,
+// and to the list shared by all groups when share_avatar_hashes is on. Automatic verdicts are never shared,
+// a single false positive would otherwise spread to every group.
+func learnConfirmedAvatar(bot *telego.Bot, groupID int64, userID int64) {
+	hash, hasPhoto, err := rules.FetchAvatarHash(bot, userID)
+	if err != nil || !hasPhoto {
+		if err != nil {
+			logger.Warningf("Error getting avatar of banned user %d: %v", userID, err)
+		}
+		return
+	}
+	if err := saveAvatarHash(groupID, userID, hash, "admin_ban"); err != nil {
+		logger.Warningf("Error blocking avatar of banned user %d: %v", userID, err)
+	}
+	if globalConfig == nil || !globalConfig.Antispam.ShareAvatarHashes {
+		return
+	}
+	if err := saveAvatarHash(0, userID, hash, "admin_ban"); err != nil {
+		logger.Warningf("Error sharing avatar of banned user %d: %v", userID, err)
+	}
+}
+
+// handleAvatarBlockCallback adds the avatar of a reported user to the group's spam avatar list
+func handleAvatarBlockCallback(bot *telego.Bot, query telego.CallbackQuery) error {
+	groupID, userID, err := getGroupAndUserID(query.Data)
+	if err != nil {
+		logger.Warningf("Invalid callback data in avatar callback: %s", query.Data)
+		return nil
+	}
+
+	isAdmin, err := checkAdminQuery(bot, query, groupID)
+	if !isAdmin {
+		return err
+	}
+
+	language := GetBotQueryLang(bot, &query)
+
+	text := models.GetTranslation(language, "avatar_blocked")
+	blocked, err := blockUserAvatar(bot, groupID, userID, "admin")
+	if err != nil {
+		logger.Warningf("Error blocking avatar of user %d: %v", userID, err)
+		text = models.GetTranslation(language, "avatar_block_failed")
+	} else if !blocked {
+		text = models.GetTranslation(language, "avatar_not_found")
+	}
+
+	err = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            text,
+	})
+	if err != nil {
+		logger.Warningf("Error answering callback query: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// pendingBlocklistArgs keeps the /blocklist arguments of a user until a group is selected
+var (
+	pendingBlocklistArgs   = make(map[int64][]string)
+	pendingBlocklistArgsMu sync.Mutex
+)
+
+// handleBlocklistCommand handles /blocklist add|del|list
+//
+//	/blocklist add <field> <match_type> <action> <pattern>
+//	/blocklist del <id>
+//	/blocklist list
+func handleBlocklistCommand(bot *telego.Bot, message telego.Message, args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	if !validBlocklistArgs(args) {
+		return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(GetBotLang(bot, message), "blocklist_usage"))
+	}
+
+	pendingBlocklistArgsMu.Lock()
+	pendingBlocklistArgs[message.From.ID] = args
+	pendingBlocklistArgsMu.Unlock()
+
+	return handleToggleCommand(bot, message, "blocklist")
+}
+
+// validBlocklistArgs checks the shape of the arguments, values are validated when the pattern is compiled
+func validBlocklistArgs(args []string) bool {
+	switch args[0] {
+	case "list":
+		return len(args) == 1
+	case "del":
+		if len(args) != 2 {
+			return false
+		}
+		_, err := strconv.ParseUint(args[1], 10, 64)
+		return err == nil
+	case "add":
+		return len(args) >= 5
+	}
+	return false
+}
+
+// executeBlocklistCommand runs the pending /blocklist arguments of the user against the selected group
+func executeBlocklistCommand(bot *telego.Bot, query telego.CallbackQuery, groupID int64, language string) error {
+	pendingBlocklistArgsMu.Lock()
+	args, ok := pendingBlocklistArgs[query.From.ID]
+	delete(pendingBlocklistArgs, query.From.ID)
+	pendingBlocklistArgsMu.Unlock()
+	if !ok {
+		args = []string{"list"}
+	}
+
+	if query.Message == nil {
+		logger.Warningf("Query message is nil in blocklist command")
+		return nil
+	}
+
+	var message telego.Message
+	switch msg := query.Message.(type) {
+	case *telego.Message:
+		message = *msg
+	default:
+		logger.Warningf("Unexpected message type in blocklist command: %T", msg)
+		return nil
+	}
+
+	var text string
+	switch args[0] {
+	case "add":
+		pattern := &models.BlocklistPattern{
+			GroupID:   groupID,
+			Field:     args[1],
+			MatchType: args[2],
+			Action:    args[3],
+			Pattern:   strings.Join(args[4:], " "),
+			CreatedBy: query.From.ID,
+		}
+		if err := service.AddBlocklistPattern(pattern); err != nil {
+			logger.Warningf("Error adding blocklist pattern for group %d: %v", groupID, err)
+			text = fmt.Sprintf(models.GetTranslation(language, "blocklist_invalid"), html.EscapeString(err.Error())) +
+				"\n\n" + models.GetTranslation(language, "blocklist_usage")
+		} else {
+			logger.Infof("Blocklist pattern %d added to group %d by %d: %s %s %s %q", pattern.ID, groupID, query.From.ID, pattern.Field, pattern.MatchType, pattern.Action, pattern.Pattern)
+			text = fmt.Sprintf(models.GetTranslation(language, "blocklist_added"), pattern.ID)
+		}
+
+	case "del":
+		id, _ := strconv.ParseUint(args[1], 10, 64)
+		found, err := service.RemoveBlocklistPattern(groupID, uint(id))
+		if err != nil {
+			logger.Warningf("Error deleting blocklist pattern %d of group %d: %v", id, groupID, err)
+		}
+		if found {
+			text = fmt.Sprintf(models.GetTranslation(language, "blocklist_deleted"), id)
+		} else {
+			text = fmt.Sprintf(models.GetTranslation(language, "blocklist_not_found"), id)
+		}
+
+	default:
+		text = formatBlocklist(groupID, language)
+	}
+
+	return sendHTMLReply(bot, message.Chat.ID, text)
+}
+
+// formatBlocklist renders the patterns of a group
+func formatBlocklist(groupID int64, language string) string {
+	patterns := service.GetBlocklistPatterns(groupID)
+	if len(patterns) == 0 {
+		return models.GetTranslation(language, "blocklist_empty")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(models.GetTranslation(language, "blocklist_title"))
+	for _, pattern := range patterns {
+		sb.WriteString(fmt.Sprintf("\n<b>#%d</b> %s · %s · %s\n<code>%s</code>",
+			pattern.ID, pattern.Field, pattern.MatchType, pattern.Action, html.EscapeString(pattern.Pattern)))
+	}
+	return sb.String()
+}
@@ -12,6 +12,8 @@ import (
 	"tg-antispam/internal/crash"
 	"tg-antispam/internal/logger"
 	"tg-antispam/internal/models"
+	"tg-antispam/internal/provider"
+	"tg-antispam/internal/rules"
 	"tg-antispam/internal/service"
 )
 
@@ -48,6 +50,12 @@ func HandleCallbackQuery(bot *telego.Bot, query telego.CallbackQuery) error {
 		return handleBanCallback(bot, query)
 	} else if strings.HasPrefix(query.Data, "wait_sec:") {
 		return handleWaitSecCallback(bot, query)
+	} else if isNumericSettingCallback(query.Data) {
+		return handleNumericSettingCallback(bot, query)
+	} else if strings.HasPrefix(query.Data, "avatar:") {
+		return handleAvatarBlockCallback(bot, query)
+	} else if strings.HasPrefix(query.Data, "provider:") {
+		return handleProviderCallback(bot, query)
 	}
 
 	return nil
@@ -86,6 +94,8 @@ func handleUnbanCallback(bot *telego.Bot, query telego.CallbackQuery) error {
 
 	// Unrestrict the user
 	UnrestrictUser(bot, groupID, userID)
+	// The restriction was wrong, the messages behind it are ham
+	learnFromUnban(groupID, userID)
 	// Update ban_records to mark as unbanned
 	service.UnbanUserInGroup(groupID, userID, "admin")
 
@@ -157,6 +167,9 @@ func handleBanCallback(bot *telego.Bot, query telego.CallbackQuery) error {
 		logger.Warningf("Error restricting user: %v", err)
 		return err
 	}
+	// The admin confirmed the original restriction, the message behind it is spam after all
+	learnFromBan(groupID, userID)
+	learnConfirmedAvatar(bot, groupID, userID)
 
 	// Notify the admin that the action was successful
 	err = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
@@ -509,6 +522,152 @@ func handleActionSelectionCallback(bot *telego.Bot, query telego.CallbackQuery)
 			updateMessage = models.GetTranslation(language, "bio_link_ban_disabled")
 		}
 
+	case "toggle_avatar_hash":
+		// 切换垃圾头像匹配设置
+		groupInfo.BanAvatarHash = !groupInfo.BanAvatarHash
+		if groupInfo.BanAvatarHash {
+			updateMessage = models.GetTranslation(language, "avatar_hash_ban_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "avatar_hash_ban_disabled")
+		}
+
+	case "toggle_no_avatar":
+		// 切换无头像用户检查设置
+		groupInfo.BanNoAvatar = !groupInfo.BanNoAvatar
+		if groupInfo.BanNoAvatar {
+			updateMessage = models.GetTranslation(language, "no_avatar_ban_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "no_avatar_ban_disabled")
+		}
+
+	case "toggle_impersonation":
+		// 切换冒充管理员检查设置
+		groupInfo.BanImpersonation = !groupInfo.BanImpersonation
+		if groupInfo.BanImpersonation {
+			updateMessage = models.GetTranslation(language, "impersonation_ban_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "impersonation_ban_disabled")
+		}
+
+	case "toggle_invisible_chars":
+		// 切换名字隐藏字符检查设置
+		groupInfo.BanInvisibleChars = !groupInfo.BanInvisibleChars
+		if groupInfo.BanInvisibleChars {
+			updateMessage = models.GetTranslation(language, "invisible_chars_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "invisible_chars_disabled")
+		}
+
+	case "toggle_message_scan":
+		// 切换消息内容扫描设置
+		groupInfo.EnableMessageScan = !groupInfo.EnableMessageScan
+		if groupInfo.EnableMessageScan {
+			updateMessage = models.GetTranslation(language, "message_scan_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "message_scan_disabled")
+		}
+
+	case "toggle_message_provider":
+		// 切换消息发送者黑名单检查设置
+		groupInfo.MessageProviderCheck = !groupInfo.MessageProviderCheck
+		if groupInfo.MessageProviderCheck {
+			updateMessage = models.GetTranslation(language, "message_provider_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "message_provider_disabled")
+		}
+
+	case "toggle_ai_check":
+		// 切换 AI 消息检查设置
+		groupInfo.EnableAicheck = !groupInfo.EnableAicheck
+		if groupInfo.EnableAicheck {
+			updateMessage = models.GetTranslation(language, "ai_check_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "ai_check_disabled")
+		}
+
+	case "toggle_ai_profile":
+		// 切换入群资料 AI 检查设置
+		groupInfo.EnableAiProfile = !groupInfo.EnableAiProfile
+		if groupInfo.EnableAiProfile {
+			updateMessage = models.GetTranslation(language, "ai_profile_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "ai_profile_disabled")
+		}
+
+	case "toggle_edit_scan":
+		// 切换编辑消息检查设置
+		groupInfo.EnableEditScan = !groupInfo.EnableEditScan
+		if groupInfo.EnableEditScan {
+			updateMessage = models.GetTranslation(language, "edit_scan_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "edit_scan_disabled")
+		}
+
+	case "toggle_inline_bots":
+		// 切换内联机器人消息拦截设置
+		groupInfo.BlockInlineBots = !groupInfo.BlockInlineBots
+		if groupInfo.BlockInlineBots {
+			updateMessage = models.GetTranslation(language, "inline_bots_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "inline_bots_disabled")
+		}
+
+	case "forward_channels":
+		// 切换频道转发策略：允许、全部拦截、仅允许白名单频道
+		groupInfo.ForwardChannelPolicy = nextForwardChannelPolicy(groupInfo)
+		updateMessage = fmt.Sprintf(models.GetTranslation(language, "forward_channels_changed"),
+			models.GetTranslation(language, "forward_channels_"+groupInfo.ForwardChannelPolicy))
+
+	case "forward_channel_action":
+		// 切换被拦截频道转发的处理方式：删除或删除并限制
+		groupInfo.ForwardChannelAction = toggleForwardAction(groupInfo.ForwardChannelAction)
+		updateMessage = fmt.Sprintf(models.GetTranslation(language, "forward_action_changed"),
+			models.GetTranslation(language, "message_action_"+groupInfo.ForwardChannelAction))
+
+	case "toggle_hidden_forwards":
+		// 切换隐藏账号用户转发拦截设置
+		groupInfo.BlockHiddenForwards = !groupInfo.BlockHiddenForwards
+		if groupInfo.BlockHiddenForwards {
+			updateMessage = models.GetTranslation(language, "hidden_forwards_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "hidden_forwards_disabled")
+		}
+
+	case "hidden_forward_action":
+		// 切换隐藏账号用户转发的处理方式：删除或删除并限制
+		groupInfo.HiddenForwardAction = toggleForwardAction(groupInfo.HiddenForwardAction)
+		updateMessage = fmt.Sprintf(models.GetTranslation(language, "forward_action_changed"),
+			models.GetTranslation(language, "message_action_"+groupInfo.HiddenForwardAction))
+
+	case "toggle_bayes":
+		// 切换本地贝叶斯分类器设置
+		groupInfo.EnableBayes = !groupInfo.EnableBayes
+		if groupInfo.EnableBayes {
+			updateMessage = models.GetTranslation(language, "bayes_enabled")
+		} else {
+			updateMessage = models.GetTranslation(language, "bayes_disabled")
+		}
+
+	case "toggle_message_action":
+		// 切换违规消息的处理方式：删除或删除并限制
+		if groupInfo.MessageAction == rules.MessageActionDelete {
+			groupInfo.MessageAction = rules.MessageActionRestrict
+			updateMessage = models.GetTranslation(language, "message_action_restrict_enabled")
+		} else {
+			groupInfo.MessageAction = rules.MessageActionDelete
+			updateMessage = models.GetTranslation(language, "message_action_delete_enabled")
+		}
+
+	case "toggle_age_challenge":
+		// 切换年轻账号的处理方式：限制或验证
+		if groupInfo.AccountAgeAction == rules.AccountAgeActionChallenge {
+			groupInfo.AccountAgeAction = rules.AccountAgeActionRestrict
+			updateMessage = models.GetTranslation(language, "age_challenge_disabled")
+		} else {
+			groupInfo.AccountAgeAction = rules.AccountAgeActionChallenge
+			updateMessage = models.GetTranslation(language, "age_challenge_enabled")
+		}
+
 	case "toggle_notifications":
 		// 切换通知设置
 		groupInfo.EnableNotification = !groupInfo.EnableNotification
@@ -525,6 +684,34 @@ func handleActionSelectionCallback(bot *telego.Bot, query telego.CallbackQuery)
 	case "wait_sec":
 		// 显示等待时间选择界面
 		return showWaitSecSelection(bot, query, groupID, language)
+
+	case "restrict_score", "ban_score", "emoji_min_count", "emoji_ratio", "username_threshold", "min_account_age", "edit_window":
+		// 显示数值设置选择界面
+		return showNumericSelection(bot, query, groupID, language, action)
+
+	case "blocklist_providers":
+		// 显示黑名单数据源选择界面
+		return showProviderSelection(bot, query, groupInfo, language)
+
+	case "bio_allow", "bio_deny", "inline_bot_allow", "forward_allow", "domain_allow", "domain_deny":
+		// 执行简介白名单/黑名单、内联机器人白名单、转发频道白名单、链接域名白名单/黑名单命令
+		return executeGroupListCommand(bot, query, groupID, action, language)
+
+	case "ai_policy":
+		// 执行 AI 审核策略命令
+		return executeAiPolicyCommand(bot, query, groupInfo, language)
+
+	case "flood":
+		// 执行刷屏限制命令
+		return executeFloodCommand(bot, query, groupInfo, language)
+
+	case "duplicates":
+		// 执行跨群重复消息限制命令
+		return executeDuplicatesCommand(bot, query, groupInfo, language)
+
+	case "blocklist":
+		// 执行自定义黑名单命令
+		return executeBlocklistCommand(bot, query, groupID, language)
 	}
 
 	// 保存群组设置
@@ -664,6 +851,116 @@ func showWaitSecSelection(bot *telego.Bot, query telego.CallbackQuery, groupID i
 	return err
 }
 
+// providerSelectionKeyboard lists the registered blocklist providers with their state in the group
+func providerSelectionKeyboard(groupInfo *models.GroupInfo, language string) [][]telego.InlineKeyboardButton {
+	var keyboard [][]telego.InlineKeyboardButton
+	for _, name := range provider.Names() {
+		status := models.GetTranslation(language, getBoolStatusText(groupInfo.UsesBlocklistProvider(name)))
+		keyboard = append(keyboard, []telego.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s: %s", name, status),
+				CallbackData: fmt.Sprintf("provider:%s:%d", name, groupInfo.GroupID),
+			},
+		})
+	}
+	return keyboard
+}
+
+// showProviderSelection displays the blocklist providers the group can switch on and off
+func showProviderSelection(bot *telego.Bot, query telego.CallbackQuery, groupInfo *models.GroupInfo, language string) error {
+	if query.Message == nil {
+		logger.Warningf("Query message is nil in provider selection")
+		return nil
+	}
+
+	var message telego.Message
+	switch msg := query.Message.(type) {
+	case *telego.Message:
+		message = *msg
+	default:
+		logger.Warningf("Unexpected message type in provider selection: %T", msg)
+		return nil
+	}
+
+	_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: message.Chat.ID},
+		Text:        models.GetTranslation(language, "select_blocklist_providers"),
+		ParseMode:   "HTML",
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: providerSelectionKeyboard(groupInfo, language)},
+	})
+	if err != nil {
+		logger.Warningf("Error sending provider selection message: %v", err)
+	}
+	return err
+}
+
+// handleProviderCallback switches a blocklist provider on or off for a group
+func handleProviderCallback(bot *telego.Bot, query telego.CallbackQuery) error {
+	// 解析回调数据: provider:name:groupID
+	parts := strings.Split(query.Data, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+	name := parts[1]
+
+	groupID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		logger.Warningf("Invalid group ID in callback: %s", parts[2])
+		return nil
+	}
+
+	// 获取群组信息
+	groupInfo := service.GetGroupInfo(bot, groupID, false)
+	if groupInfo == nil {
+		logger.Warningf("Group info not found: %d", groupID)
+		return nil
+	}
+
+	// 检查用户是否有权限
+	if groupInfo.AdminID != query.From.ID {
+		isAdmin, err := checkAdminQuery(bot, query, groupID)
+		if !isAdmin {
+			return err
+		}
+	}
+
+	// 获取语言
+	language := GetBotQueryLang(bot, &query)
+
+	// 切换数据源
+	enabled := !groupInfo.UsesBlocklistProvider(name)
+	groupInfo.SetBlocklistProvider(name, enabled)
+	service.UpdateGroupInfo(groupInfo)
+
+	key := "provider_disabled"
+	if enabled {
+		key = "provider_enabled"
+	}
+	err = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            fmt.Sprintf(models.GetTranslation(language, key), name),
+	})
+	if err != nil {
+		logger.Warningf("Error answering callback query: %v", err)
+	}
+
+	// 更新选择键盘
+	if query.Message != nil {
+		if message, ok := query.Message.(*telego.Message); ok {
+			_, err = bot.EditMessageReplyMarkup(context.Background(), &telego.EditMessageReplyMarkupParams{
+				ChatID:      telego.ChatID{ID: message.Chat.ID},
+				MessageID:   message.MessageID,
+				ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: providerSelectionKeyboard(groupInfo, language)},
+			})
+			if err != nil {
+				logger.Warningf("Error updating provider selection message: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func SendMathVerificationMessage(bot *telego.Bot, userID int64, groupID int64, query *telego.CallbackQuery) error {
 	// Generate a random math problem
 	num1 := rand.Intn(100)
@@ -779,7 +1076,7 @@ func HandleMathVerification(bot *telego.Bot, message telego.Message) error {
 	// Check if the answer is correct
 	if userAnswer == expectedAnswer.Answer {
 		// double check for premium user
-		if (message.From.IsPremium || IsRandomUsername(message.From.Username) || HasEmoji(message.From.FirstName) || HasLinksInBio(bot, message.From.ID)) && verificationAttempts[userID] >= 0 {
+		if (message.From.IsPremium || rules.ExceedsRandomUsernameThreshold(groupInfo, message.From.Username) || rules.ExceedsEmojiPolicy(groupInfo, rules.FullName(*message.From)) || rules.HasLinksInBio(bot, groupInfo, *message.From)) && verificationAttempts[userID] >= 0 {
 			verificationAttempts[userID] = -1
 			query := telego.CallbackQuery{
 				ID:      "",
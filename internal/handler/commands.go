@@ -9,6 +9,8 @@ import (
 
 	"tg-antispam/internal/logger"
 	"tg-antispam/internal/models"
+	"tg-antispam/internal/provider"
+	"tg-antispam/internal/rules"
 	"tg-antispam/internal/service"
 	"tg-antispam/internal/storage"
 )
@@ -25,6 +27,23 @@ func HandleCommand(bot *telego.Bot, message telego.Message) (bool, error) {
 		command = strings.TrimSuffix(command, "@"+bot.Username())
 	}
 
+	// /blocklist and the list commands take arguments, so they are matched on the first word
+	fields := strings.Fields(command)
+	switch name := strings.TrimSuffix(fields[0], "@"+bot.Username()); name {
+	case "/blocklist":
+		return true, handleBlocklistCommand(bot, message, fields[1:])
+	case "/bio_allow", "/bio_deny", "/inline_bot_allow", "/forward_allow", "/domain_allow", "/domain_deny":
+		return true, handleGroupListCommand(bot, message, strings.TrimPrefix(name, "/"), fields[1:])
+	case "/train":
+		return true, handleTrainCommand(bot, message, fields[1:])
+	case "/ai_policy":
+		return true, handleAiPolicyCommand(bot, message, commandArgument(command, fields[0]))
+	case "/flood":
+		return true, handleFloodCommand(bot, message, fields[1:])
+	case "/duplicates":
+		return true, handleDuplicatesCommand(bot, message, fields[1:])
+	}
+
 	switch command {
 	case "/help", "/start", "/start help":
 		return true, sendHelpMessage(bot, message)
@@ -46,6 +65,54 @@ func HandleCommand(bot *telego.Bot, message telego.Message) (bool, error) {
 		return true, handleToggleCommand(bot, message, "language_group")
 	case "/wait_sec":
 		return true, handleToggleCommand(bot, message, "wait_sec")
+	case "/restrict_score":
+		return true, handleToggleCommand(bot, message, "restrict_score")
+	case "/ban_score":
+		return true, handleToggleCommand(bot, message, "ban_score")
+	case "/emoji_min_count":
+		return true, handleToggleCommand(bot, message, "emoji_min_count")
+	case "/emoji_ratio":
+		return true, handleToggleCommand(bot, message, "emoji_ratio")
+	case "/username_threshold":
+		return true, handleToggleCommand(bot, message, "username_threshold")
+	case "/min_account_age":
+		return true, handleToggleCommand(bot, message, "min_account_age")
+	case "/toggle_age_challenge":
+		return true, handleToggleCommand(bot, message, "toggle_age_challenge")
+	case "/toggle_avatar_hash":
+		return true, handleToggleCommand(bot, message, "toggle_avatar_hash")
+	case "/toggle_no_avatar":
+		return true, handleToggleCommand(bot, message, "toggle_no_avatar")
+	case "/toggle_impersonation":
+		return true, handleToggleCommand(bot, message, "toggle_impersonation")
+	case "/toggle_invisible_chars":
+		return true, handleToggleCommand(bot, message, "toggle_invisible_chars")
+	case "/toggle_message_scan":
+		return true, handleToggleCommand(bot, message, "toggle_message_scan")
+	case "/toggle_message_provider":
+		return true, handleToggleCommand(bot, message, "toggle_message_provider")
+	case "/toggle_ai_check":
+		return true, handleToggleCommand(bot, message, "toggle_ai_check")
+	case "/toggle_ai_profile":
+		return true, handleToggleCommand(bot, message, "toggle_ai_profile")
+	case "/toggle_edit_scan":
+		return true, handleToggleCommand(bot, message, "toggle_edit_scan")
+	case "/toggle_inline_bots":
+		return true, handleToggleCommand(bot, message, "toggle_inline_bots")
+	case "/forward_channels":
+		return true, handleToggleCommand(bot, message, "forward_channels")
+	case "/forward_channel_action":
+		return true, handleToggleCommand(bot, message, "forward_channel_action")
+	case "/toggle_hidden_forwards":
+		return true, handleToggleCommand(bot, message, "toggle_hidden_forwards")
+	case "/hidden_forward_action":
+		return true, handleToggleCommand(bot, message, "hidden_forward_action")
+	case "/edit_window":
+		return true, handleToggleCommand(bot, message, "edit_window")
+	case "/toggle_message_action":
+		return true, handleToggleCommand(bot, message, "toggle_message_action")
+	case "/toggle_bayes":
+		return true, handleToggleCommand(bot, message, "toggle_bayes")
 	case "/language":
 		return true, handleLanguageCommand(bot, message)
 	case "/self_unban":
@@ -73,7 +140,9 @@ func handleStatusCommand(bot *telego.Bot, message telego.Message) error {
 		return PrivateChatWarning(bot, message)
 	}
 
-	statusText := GetDetailedStatus()
+	statusText := GetDetailedStatus() + GetAIUsageStatus(message.From.ID) + GetEditSpamStatus(message.From.ID)
+	// only backslashes and backticks need escaping inside a MarkdownV2 code block
+	statusText = strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(statusText)
 	_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
 		ChatID:    telego.ChatID{ID: message.Chat.ID},
 		Text:      fmt.Sprintf("```\n%s\n```", statusText),
@@ -129,7 +198,7 @@ func handleLanguageCommand(bot *telego.Bot, message telego.Message) error {
 func sendHelpMessage(bot *telego.Bot, message telego.Message) error {
 	language := GetBotLang(bot, message)
 
-	helpText := fmt.Sprintf("<b>%s</b>\n\n%s\n\n<b>%s</b>\n%s\n%s\n%s\n\n<b>%s</b>\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n\n<b>%s</b>",
+	helpText := fmt.Sprintf("<b>%s</b>\n\n%s\n\n<b>%s</b>\n%s\n%s\n%s\n\n<b>%s</b>\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n%s\n\n<b>%s</b>",
 		models.GetTranslation(language, "help_title"),
 		models.GetTranslation(language, "help_description"),
 		models.GetTranslation(language, "general_commands"),
@@ -147,6 +216,41 @@ func sendHelpMessage(bot *telego.Bot, message telego.Message) error {
 		models.GetTranslation(language, "help_cmd_toggle_notifications"),
 		models.GetTranslation(language, "help_cmd_language_group"),
 		models.GetTranslation(language, "help_cmd_wait_sec"),
+		models.GetTranslation(language, "help_cmd_restrict_score"),
+		models.GetTranslation(language, "help_cmd_ban_score"),
+		models.GetTranslation(language, "help_cmd_emoji_min_count"),
+		models.GetTranslation(language, "help_cmd_emoji_ratio"),
+		models.GetTranslation(language, "help_cmd_username_threshold"),
+		models.GetTranslation(language, "help_cmd_min_account_age"),
+		models.GetTranslation(language, "help_cmd_toggle_age_challenge"),
+		models.GetTranslation(language, "help_cmd_toggle_avatar_hash"),
+		models.GetTranslation(language, "help_cmd_toggle_no_avatar"),
+		models.GetTranslation(language, "help_cmd_toggle_impersonation"),
+		models.GetTranslation(language, "help_cmd_toggle_invisible_chars"),
+		models.GetTranslation(language, "help_cmd_toggle_message_scan"),
+		models.GetTranslation(language, "help_cmd_toggle_message_provider"),
+		models.GetTranslation(language, "help_cmd_toggle_ai_check"),
+		models.GetTranslation(language, "help_cmd_toggle_ai_profile"),
+		models.GetTranslation(language, "help_cmd_toggle_message_action"),
+		models.GetTranslation(language, "help_cmd_toggle_bayes"),
+		models.GetTranslation(language, "help_cmd_train"),
+		models.GetTranslation(language, "help_cmd_ai_policy"),
+		models.GetTranslation(language, "help_cmd_flood"),
+		models.GetTranslation(language, "help_cmd_duplicates"),
+		models.GetTranslation(language, "help_cmd_toggle_edit_scan"),
+		models.GetTranslation(language, "help_cmd_edit_window"),
+		models.GetTranslation(language, "help_cmd_blocklist"),
+		models.GetTranslation(language, "help_cmd_bio_allow"),
+		models.GetTranslation(language, "help_cmd_bio_deny"),
+		models.GetTranslation(language, "help_cmd_toggle_inline_bots"),
+		models.GetTranslation(language, "help_cmd_inline_bot_allow"),
+		models.GetTranslation(language, "help_cmd_forward_channels"),
+		models.GetTranslation(language, "help_cmd_forward_channel_action"),
+		models.GetTranslation(language, "help_cmd_forward_allow"),
+		models.GetTranslation(language, "help_cmd_toggle_hidden_forwards"),
+		models.GetTranslation(language, "help_cmd_hidden_forward_action"),
+		models.GetTranslation(language, "help_cmd_domain_allow"),
+		models.GetTranslation(language, "help_cmd_domain_deny"),
 		models.GetTranslation(language, "help_note"),
 	)
 
@@ -235,7 +339,7 @@ func showGroupSelection(bot *telego.Bot, message telego.Message, action string)
 		switch action {
 		case "settings":
 			return showGroupSettings(bot, message, group.GroupID)
-		case "toggle_premium", "toggle_cas", "toggle_random_username", "toggle_emoji_name", "toggle_bio_link", "toggle_notifications", "language_group":
+		case "toggle_premium", "toggle_cas", "toggle_random_username", "toggle_emoji_name", "toggle_bio_link", "toggle_notifications", "language_group", "restrict_score", "ban_score", "emoji_min_count", "emoji_ratio", "username_threshold", "min_account_age", "toggle_age_challenge", "toggle_avatar_hash", "toggle_no_avatar", "toggle_impersonation", "toggle_invisible_chars", "toggle_message_scan", "toggle_message_provider", "toggle_ai_check", "toggle_ai_profile", "toggle_message_action", "toggle_bayes", "ai_policy", "flood", "duplicates", "toggle_edit_scan", "edit_window", "toggle_inline_bots", "forward_channels", "forward_channel_action", "toggle_hidden_forwards", "hidden_forward_action", "blocklist", "bio_allow", "bio_deny", "inline_bot_allow", "forward_allow", "domain_allow", "domain_deny":
 			// 模拟回调数据处理，创建一个回调查询对象
 			callbackData := fmt.Sprintf("action:%s:%d", action, group.GroupID)
 			query := telego.CallbackQuery{
@@ -306,6 +410,15 @@ func buildGroupSettingsMessageParts(groupInfo *models.GroupInfo, language string
 	randomUsernameStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanRandomUsername))
 	emojiNameStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanEmojiName))
 	bioLinkStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanBioLink))
+	avatarHashStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanAvatarHash))
+	noAvatarStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanNoAvatar))
+	impersonationStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanImpersonation))
+	invisibleCharsStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.BanInvisibleChars))
+	messageScanStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.EnableMessageScan))
+	messageProviderStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.MessageProviderCheck))
+	aiCheckStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.EnableAicheck))
+	aiProfileStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.EnableAiProfile))
+	bayesStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.EnableBayes))
 	notificationsStatus := models.GetTranslation(language, getBoolStatusText(groupInfo.EnableNotification))
 	langName := getLanguageName(groupInfo.Language)
 
@@ -314,9 +427,75 @@ func buildGroupSettingsMessageParts(groupInfo *models.GroupInfo, language string
 	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_random_username"), randomUsernameStatus) + "\n"
 	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_emoji_name"), emojiNameStatus) + "\n"
 	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_bio_link"), bioLinkStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_avatar_hash"), avatarHashStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_no_avatar"), noAvatarStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_impersonation"), impersonationStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_invisible_chars"), invisibleCharsStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_message_scan"), messageScanStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_message_provider"), messageProviderStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_ai_check"), aiCheckStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_ai_profile"), aiProfileStatus) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_bayes"), bayesStatus) + "\n"
+	aiPolicy := models.GetTranslation(language, "ai_policy_default")
+	if groupInfo.AiPolicy != "" {
+		aiPolicy = models.GetTranslation(language, "ai_policy_custom")
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_ai_policy"), aiPolicy) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_flood"), formatFloodLimits(groupInfo, language)) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_duplicates"), formatDuplicateLimits(groupInfo, language)) + "\n"
+	editWindow := models.GetTranslation(language, "disabled")
+	if groupInfo.EditWindowMinutes > 0 {
+		editWindow = fmt.Sprintf("%d %s", groupInfo.EditWindowMinutes, models.GetTranslation(language, "minutes"))
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_edit_scan"),
+		models.GetTranslation(language, getBoolStatusText(groupInfo.EnableEditScan)), editWindow) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_inline_bots"),
+		models.GetTranslation(language, getBoolStatusText(groupInfo.BlockInlineBots))) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_forward_channels"),
+		models.GetTranslation(language, "forward_channels_"+rules.ForwardChannelPolicy(groupInfo)),
+		models.GetTranslation(language, "message_action_"+forwardActionName(groupInfo.ForwardChannelAction))) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_hidden_forwards"),
+		models.GetTranslation(language, getBoolStatusText(groupInfo.BlockHiddenForwards)),
+		models.GetTranslation(language, "message_action_"+forwardActionName(groupInfo.HiddenForwardAction))) + "\n"
+	messageAction := models.GetTranslation(language, "message_action_"+rules.MessageActionRestrict)
+	if groupInfo.MessageAction == rules.MessageActionDelete {
+		messageAction = models.GetTranslation(language, "message_action_"+rules.MessageActionDelete)
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_message_action"), messageAction) + "\n"
 	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_notifications"), notificationsStatus) + "\n"
 	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_language"), langName) + "\n"
 	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_wait_sec"), fmt.Sprintf("%d", groupInfo.WaitSec)) + "\n"
+	banScore := models.GetTranslation(language, "never")
+	if groupInfo.BanScore > 0 {
+		banScore = fmt.Sprintf("%d", groupInfo.BanScore)
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_score"), groupInfo.RestrictScore, banScore) + "\n"
+	emojiRatio := models.GetTranslation(language, "disabled")
+	if groupInfo.EmojiRatio > 0 {
+		emojiRatio = fmt.Sprintf("%d%%", groupInfo.EmojiRatio)
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_emoji_policy"), groupInfo.EmojiMinCount, emojiRatio) + "\n"
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_username_threshold"), groupInfo.RandomUsernameThreshold) + "\n"
+	var providers []string
+	for _, name := range provider.Names() {
+		if groupInfo.UsesBlocklistProvider(name) {
+			providers = append(providers, name)
+		}
+	}
+	providerList := models.GetTranslation(language, "disabled")
+	if len(providers) > 0 {
+		providerList = strings.Join(providers, ", ")
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_blocklist_providers"), providerList) + "\n"
+	minAccountAge := models.GetTranslation(language, "disabled")
+	if groupInfo.MinAccountAgeDays > 0 {
+		minAccountAge = fmt.Sprintf("%d %s", groupInfo.MinAccountAgeDays, models.GetTranslation(language, "days"))
+	}
+	accountAgeAction := models.GetTranslation(language, "account_age_action_"+rules.AccountAgeActionRestrict)
+	if groupInfo.AccountAgeAction == rules.AccountAgeActionChallenge {
+		accountAgeAction = models.GetTranslation(language, "account_age_action_"+rules.AccountAgeActionChallenge)
+	}
+	settingsText += fmt.Sprintf(models.GetTranslation(language, "settings_account_age"), minAccountAge, accountAgeAction) + "\n"
 
 	// 创建设置按钮
 	keyboard := [][]telego.InlineKeyboardButton{
@@ -350,6 +529,64 @@ func buildGroupSettingsMessageParts(groupInfo *models.GroupInfo, language string
 				CallbackData: fmt.Sprintf("action:toggle_bio_link:%d", groupID),
 			},
 		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_avatar_hash"),
+				CallbackData: fmt.Sprintf("action:toggle_avatar_hash:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "toggle_no_avatar"),
+				CallbackData: fmt.Sprintf("action:toggle_no_avatar:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_impersonation"),
+				CallbackData: fmt.Sprintf("action:toggle_impersonation:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_invisible_chars"),
+				CallbackData: fmt.Sprintf("action:toggle_invisible_chars:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_message_scan"),
+				CallbackData: fmt.Sprintf("action:toggle_message_scan:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "toggle_message_action"),
+				CallbackData: fmt.Sprintf("action:toggle_message_action:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_message_provider"),
+				CallbackData: fmt.Sprintf("action:toggle_message_provider:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "toggle_ai_check"),
+				CallbackData: fmt.Sprintf("action:toggle_ai_check:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_bayes"),
+				CallbackData: fmt.Sprintf("action:toggle_bayes:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "toggle_ai_profile"),
+				CallbackData: fmt.Sprintf("action:toggle_ai_profile:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "change_blocklist_providers"),
+				CallbackData: fmt.Sprintf("action:blocklist_providers:%d", groupID),
+			},
+		},
 		{
 			{
 				Text:         models.GetTranslation(language, "toggle_notifications"),
@@ -368,6 +605,78 @@ func buildGroupSettingsMessageParts(groupInfo *models.GroupInfo, language string
 				CallbackData: fmt.Sprintf("action:wait_sec:%d", groupID),
 			},
 		},
+		{
+			{
+				Text:         models.GetTranslation(language, "change_restrict_score"),
+				CallbackData: fmt.Sprintf("action:restrict_score:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "change_ban_score"),
+				CallbackData: fmt.Sprintf("action:ban_score:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "change_emoji_min_count"),
+				CallbackData: fmt.Sprintf("action:emoji_min_count:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "change_emoji_ratio"),
+				CallbackData: fmt.Sprintf("action:emoji_ratio:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "change_username_threshold"),
+				CallbackData: fmt.Sprintf("action:username_threshold:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "change_min_account_age"),
+				CallbackData: fmt.Sprintf("action:min_account_age:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "toggle_age_challenge"),
+				CallbackData: fmt.Sprintf("action:toggle_age_challenge:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_edit_scan"),
+				CallbackData: fmt.Sprintf("action:toggle_edit_scan:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "change_edit_window"),
+				CallbackData: fmt.Sprintf("action:edit_window:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_inline_bots"),
+				CallbackData: fmt.Sprintf("action:toggle_inline_bots:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "change_forward_channels"),
+				CallbackData: fmt.Sprintf("action:forward_channels:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "change_forward_channel_action"),
+				CallbackData: fmt.Sprintf("action:forward_channel_action:%d", groupID),
+			},
+		},
+		{
+			{
+				Text:         models.GetTranslation(language, "toggle_hidden_forwards"),
+				CallbackData: fmt.Sprintf("action:toggle_hidden_forwards:%d", groupID),
+			},
+			{
+				Text:         models.GetTranslation(language, "change_hidden_forward_action"),
+				CallbackData: fmt.Sprintf("action:hidden_forward_action:%d", groupID),
+			},
+		},
 	}
 	return settingsText, keyboard
 }
@@ -422,6 +731,19 @@ func PrivateChatWarning(bot *telego.Bot, message telego.Message) error {
 	return err
 }
 
+// sendHTMLReply sends an HTML formatted message to a chat
+func sendHTMLReply(bot *telego.Bot, chatID int64, text string) error {
+	_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:    telego.ChatID{ID: chatID},
+		Text:      text,
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		logger.Warningf("Error sending reply to chat %d: %v", chatID, err)
+	}
+	return err
+}
+
 // handleSelfUnbanCommand guides a user through self-unban based on their ban records
 func handleSelfUnbanCommand(bot *telego.Bot, message telego.Message) error {
 	if message.Chat.Type != "private" {
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+)
+
+// pendingDuplicateArgs keeps the /duplicates arguments of a user until a group is selected
+var (
+	pendingDuplicateArgs   = make(map[int64][]string)
+	pendingDuplicateArgsMu sync.Mutex
+)
+
+// handleDuplicatesCommand handles /duplicates, which sets how widely a message may be repeated across groups
+//
+//	/duplicates <groups> <users>   the most distinct groups and users that may post the same message, 0 disables a limit
+//	/duplicates off                disable the detection
+//	/duplicates                    show the limits
+func handleDuplicatesCommand(bot *telego.Bot, message telego.Message, args []string) error {
+	if !validDuplicateArgs(args) {
+		return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(GetBotLang(bot, message), "duplicates_usage"))
+	}
+
+	pendingDuplicateArgsMu.Lock()
+	pendingDuplicateArgs[message.From.ID] = args
+	pendingDuplicateArgsMu.Unlock()
+
+	return handleToggleCommand(bot, message, "duplicates")
+}
+
+func validDuplicateArgs(args []string) bool {
+	switch len(args) {
+	case 0:
+		return true
+	case 1:
+		return args[0] == "off"
+	case 2:
+		for _, arg := range args {
+			if n, err := strconv.Atoi(arg); err != nil || n < 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// executeDuplicatesCommand applies the pending /duplicates command of the user to the selected group
+func executeDuplicatesCommand(bot *telego.Bot, query telego.CallbackQuery, groupInfo *models.GroupInfo, language string) error {
+	pendingDuplicateArgsMu.Lock()
+	args := pendingDuplicateArgs[query.From.ID]
+	delete(pendingDuplicateArgs, query.From.ID)
+	pendingDuplicateArgsMu.Unlock()
+
+	message, ok := query.Message.(*telego.Message)
+	if !ok {
+		logger.Warningf("Unexpected message type in duplicates command: %T", query.Message)
+		return nil
+	}
+
+	switch len(args) {
+	case 1:
+		groupInfo.DuplicateGroups, groupInfo.DuplicateUsers = 0, 0
+	case 2:
+		groupInfo.DuplicateGroups, _ = strconv.Atoi(args[0])
+		groupInfo.DuplicateUsers, _ = strconv.Atoi(args[1])
+	}
+	if len(args) > 0 {
+		service.UpdateGroupInfo(groupInfo)
+		logger.Infof("duplicates %v in group %d by %d", args, groupInfo.GroupID, query.From.ID)
+	}
+
+	text := fmt.Sprintf(models.GetTranslation(language, "duplicates_current"), formatDuplicateLimits(groupInfo, language)) +
+		"\n\n" + models.GetTranslation(language, "duplicates_usage")
+	return sendHTMLReply(bot, message.Chat.ID, text)
+}
+
+// formatDuplicateLimits describes the group's duplicate limits
+func formatDuplicateLimits(groupInfo *models.GroupInfo, language string) string {
+	if !rules.DuplicateEnabled(groupInfo) {
+		return models.GetTranslation(language, "disabled")
+	}
+	limit := func(n int) string {
+		if n <= 0 {
+			return "-"
+		}
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf(models.GetTranslation(language, "duplicates_limits"), limit(groupInfo.DuplicateGroups), limit(groupInfo.DuplicateUsers))
+}
+
+// purgeDuplicateCopies deletes the other copies of a message flagged as duplicate spam and restricts their senders.
+// Copies in groups without duplicate limits are removed as well, copies in groups with limits only once they exceed
+// them: until then they stay unclaimed and are removed if a later copy crosses them. Admins are left alone.
+func purgeDuplicateCopies(bot *telego.Bot, message telego.Message, result rules.Result, text string) {
+	decision := rules.Decision{Verdict: result.Verdict, Reason: result.Reason, Evidence: result.Evidence, Results: []rules.Result{result}}
+	for _, c := range rules.DuplicateCopies(&message) {
+		groupInfo := service.GetGroupInfo(bot, c.ChatID, false)
+		if groupInfo == nil || !groupInfo.IsAdmin {
+			continue
+		}
+		if rules.DuplicateEnabled(groupInfo) && !rules.DuplicateExceeded(groupInfo, c.Groups, c.Users) {
+			continue
+		}
+		if isUserAdmin(bot, c.ChatID, c.User.ID) || !rules.ClaimDuplicateCopy(c) {
+			continue
+		}
+		logger.Infof("Removing copy %d of duplicate message in chat %d from user %d", c.MessageID, c.ChatID, c.User.ID)
+		DeleteMessageWithRetry(bot, c.ChatID, c.MessageID)
+		restrictMessageSender(bot, c.ChatID, c.User, decision, text)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/config"
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+)
+
+// handleEditedMessage runs edited group messages through the content checks of the message pipeline.
+// Flagged edits keep the reason of the rule that flagged them, the decision's event marks them as edits
+// and /status reports them by reason.
+func handleEditedMessage(bot *telego.Bot, message telego.Message) error {
+	if message.From == nil || message.From.IsBot || message.Chat.Type == "private" {
+		return nil
+	}
+
+	// group_id 限制：只处理指定群组
+	cfg := config.Get()
+	if cfg.Bot.GroupID != -1 && message.Chat.ID != cfg.Bot.GroupID {
+		return nil
+	}
+
+	groupInfo := service.GetGroupInfo(bot, message.Chat.ID, true)
+	if groupInfo == nil || !groupInfo.IsAdmin || !groupInfo.EnableEditScan {
+		return nil
+	}
+	if isUserAdmin(bot, message.Chat.ID, message.From.ID) {
+		return nil
+	}
+
+	subject := &rules.Subject{
+		Bot:     bot,
+		Group:   groupInfo,
+		User:    *message.From,
+		Message: &message,
+		Event:   rules.EventEdit,
+	}
+	decision := rules.Evaluate(subject)
+	if decision.Verdict == rules.VerdictPass {
+		return nil
+	}
+
+	incrementCounter(&totalEditSpam)
+	text := subject.Text()
+	if text != "" && learnsSpam(decision.Reason) {
+		service.TrainBayes(text, true)
+	}
+	logger.Infof("Edited message %d of user %d flagged by %s: %s", message.MessageID, message.From.ID, decision.Reason, decision.Evidence)
+	applyMessageDecision(bot, groupInfo, message, decision, text)
+	return nil
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+)
+
+// pendingFloodArgs keeps the /flood arguments of a user until a group is selected
+var (
+	pendingFloodArgs   = make(map[int64][]string)
+	pendingFloodArgsMu sync.Mutex
+)
+
+// handleFloodCommand handles /flood, which sets the group's flood limits
+//
+//	/flood <messages> <media> <mentions> [window seconds]   limits per window, 0 disables a limit
+//	/flood action delete|restrict|mute [minutes]           what to do with users exceeding a limit
+//	/flood off                                             disable all limits
+//	/flood                                                 show the limits
+func handleFloodCommand(bot *telego.Bot, message telego.Message, args []string) error {
+	if !validFloodArgs(args) {
+		return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(GetBotLang(bot, message), "flood_usage"))
+	}
+
+	pendingFloodArgsMu.Lock()
+	pendingFloodArgs[message.From.ID] = args
+	pendingFloodArgsMu.Unlock()
+
+	return handleToggleCommand(bot, message, "flood")
+}
+
+func validFloodArgs(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	switch args[0] {
+	case "off":
+		return len(args) == 1
+	case "action":
+		switch {
+		case len(args) == 2:
+			return args[1] == rules.FloodActionDelete || args[1] == rules.FloodActionRestrict || args[1] == rules.FloodActionMute
+		case len(args) == 3:
+			minutes, err := strconv.Atoi(args[2])
+			return args[1] == rules.FloodActionMute && err == nil && minutes > 0
+		}
+		return false
+	}
+	if len(args) != 3 && len(args) != 4 {
+		return false
+	}
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 || (i == 3 && (n == 0 || n > rules.MaxFloodWindowSec)) {
+			return false
+		}
+	}
+	return true
+}
+
+// executeFloodCommand applies the pending /flood command of the user to the selected group
+func executeFloodCommand(bot *telego.Bot, query telego.CallbackQuery, groupInfo *models.GroupInfo, language string) error {
+	pendingFloodArgsMu.Lock()
+	args := pendingFloodArgs[query.From.ID]
+	delete(pendingFloodArgs, query.From.ID)
+	pendingFloodArgsMu.Unlock()
+
+	message, ok := query.Message.(*telego.Message)
+	if !ok {
+		logger.Warningf("Unexpected message type in flood command: %T", query.Message)
+		return nil
+	}
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "off":
+			groupInfo.FloodMessages, groupInfo.FloodMedia, groupInfo.FloodMentions = 0, 0, 0
+		case "action":
+			groupInfo.FloodAction = args[1]
+			if len(args) == 3 {
+				groupInfo.FloodMuteMinutes, _ = strconv.Atoi(args[2])
+			}
+		default:
+			groupInfo.FloodMessages, _ = strconv.Atoi(args[0])
+			groupInfo.FloodMedia, _ = strconv.Atoi(args[1])
+			groupInfo.FloodMentions, _ = strconv.Atoi(args[2])
+			if len(args) == 4 {
+				groupInfo.FloodWindowSec, _ = strconv.Atoi(args[3])
+			}
+		}
+		service.UpdateGroupInfo(groupInfo)
+		logger.Infof("flood %v in group %d by %d", args, groupInfo.GroupID, query.From.ID)
+	}
+
+	text := fmt.Sprintf(models.GetTranslation(language, "flood_current"), formatFloodLimits(groupInfo, language)) +
+		"\n\n" + models.GetTranslation(language, "flood_usage")
+	return sendHTMLReply(bot, message.Chat.ID, text)
+}
+
+// formatFloodLimits describes the group's flood limits and action
+func formatFloodLimits(groupInfo *models.GroupInfo, language string) string {
+	if !rules.FloodEnabled(groupInfo) {
+		return models.GetTranslation(language, "disabled")
+	}
+
+	limit := func(n int) string {
+		if n <= 0 {
+			return "-"
+		}
+		return strconv.Itoa(n)
+	}
+	action := models.GetTranslation(language, "flood_action_"+rules.FloodActionMute)
+	switch groupInfo.FloodAction {
+	case rules.FloodActionDelete, rules.FloodActionRestrict:
+		action = models.GetTranslation(language, "flood_action_"+groupInfo.FloodAction)
+	default:
+		action = fmt.Sprintf(action, int(rules.FloodMuteDuration(groupInfo).Minutes()))
+	}
+	return fmt.Sprintf(models.GetTranslation(language, "flood_limits"),
+		limit(groupInfo.FloodMessages), limit(groupInfo.FloodMedia), limit(groupInfo.FloodMentions),
+		int(rules.FloodWindow(groupInfo).Seconds()), action)
+}
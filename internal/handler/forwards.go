@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
+)
+
+// nextForwardChannelPolicy returns the channel forward policy following the group's current one in the settings cycle
+func nextForwardChannelPolicy(groupInfo *models.GroupInfo) string {
+	current := rules.ForwardChannelPolicy(groupInfo)
+	for i, policy := range rules.ForwardChannelPolicies {
+		if policy == current {
+			return rules.ForwardChannelPolicies[(i+1)%len(rules.ForwardChannelPolicies)]
+		}
+	}
+	return rules.ForwardChannelsAllow
+}
+
+// forwardActionName returns the message action a forward policy applies, as used in translation keys
+func forwardActionName(action string) string {
+	if rules.ActionVerdict(action) == rules.VerdictDelete {
+		return rules.MessageActionDelete
+	}
+	return rules.MessageActionRestrict
+}
+
+// toggleForwardAction switches a forward policy between deleting and deleting and restricting
+func toggleForwardAction(action string) string {
+	if forwardActionName(action) == rules.MessageActionDelete {
+		return rules.MessageActionRestrict
+	}
+	return rules.MessageActionDelete
+}
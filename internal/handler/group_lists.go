@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+	"tg-antispam/internal/urlresolve"
+)
+
+// groupListNormalizers validate list values and bring them to the form the rules compare against
+var groupListNormalizers = map[string]func(string) (string, bool){
+	models.GroupListBioAllow:       rules.NormalizeBioListValue,
+	models.GroupListBioDeny:        rules.NormalizeBioListValue,
+	models.GroupListInlineBotAllow: rules.NormalizeInlineBotValue,
+	models.GroupListForwardAllow:   rules.NormalizeForwardListValue,
+	models.GroupListDomainAllow:    urlresolve.NormalizeDomain,
+	models.GroupListDomainDeny:     urlresolve.NormalizeDomain,
+}
+
+// groupListValueHints are the translation keys describing the values a list accepts
+var groupListValueHints = map[string]string{
+	models.GroupListBioAllow:       "group_list_values_bio",
+	models.GroupListBioDeny:        "group_list_values_bio",
+	models.GroupListInlineBotAllow: "group_list_values_inline_bot",
+	models.GroupListForwardAllow:   "group_list_values_forward",
+	models.GroupListDomainAllow:    "group_list_values_domain",
+	models.GroupListDomainDeny:     "group_list_values_domain",
+}
+
+// groupListUsage returns the usage of a list command with the values the list accepts
+func groupListUsage(language string, list string) string {
+	return fmt.Sprintf(models.GetTranslation(language, "group_list_usage"), list) +
+		"\n\n" + models.GetTranslation(language, groupListValueHints[list])
+}
+
+// pendingGroupListArgs keeps the list command arguments of a user until a group is selected
+var (
+	pendingGroupListArgs   = make(map[int64][]string)
+	pendingGroupListArgsMu sync.Mutex
+)
+
+// handleGroupListCommand handles the allow/deny list commands, e.g. /bio_allow add|del|list, /inline_bot_allow, /forward_allow or /domain_deny
+//
+//	/<list> add <value>
+//	/<list> del <value>
+//	/<list> list
+func handleGroupListCommand(bot *telego.Bot, message telego.Message, list string, args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	if !validGroupListArgs(args) {
+		return sendHTMLReply(bot, message.Chat.ID, groupListUsage(GetBotLang(bot, message), list))
+	}
+
+	pendingGroupListArgsMu.Lock()
+	pendingGroupListArgs[message.From.ID] = args
+	pendingGroupListArgsMu.Unlock()
+
+	return handleToggleCommand(bot, message, list)
+}
+
+func validGroupListArgs(args []string) bool {
+	switch args[0] {
+	case "list":
+		return len(args) == 1
+	case "add", "del":
+		return len(args) == 2
+	}
+	return false
+}
+
+// executeGroupListCommand runs the pending list command of the user against the selected group
+func executeGroupListCommand(bot *telego.Bot, query telego.CallbackQuery, groupID int64, list string, language string) error {
+	pendingGroupListArgsMu.Lock()
+	args, ok := pendingGroupListArgs[query.From.ID]
+	delete(pendingGroupListArgs, query.From.ID)
+	pendingGroupListArgsMu.Unlock()
+	if !ok {
+		args = []string{"list"}
+	}
+
+	if query.Message == nil {
+		logger.Warningf("Query message is nil in %s command", list)
+		return nil
+	}
+
+	var message telego.Message
+	switch msg := query.Message.(type) {
+	case *telego.Message:
+		message = *msg
+	default:
+		logger.Warningf("Unexpected message type in %s command: %T", list, msg)
+		return nil
+	}
+
+	var text string
+	switch args[0] {
+	case "add", "del":
+		value, valid := groupListNormalizers[list](args[1])
+		if !valid {
+			text = fmt.Sprintf(models.GetTranslation(language, "group_list_invalid"), html.EscapeString(args[1])) +
+				"\n\n" + groupListUsage(language, list)
+			break
+		}
+
+		var changed bool
+		var err error
+		if args[0] == "add" {
+			changed, err = service.AddGroupListValue(groupID, list, value, query.From.ID)
+		} else {
+			changed, err = service.RemoveGroupListValue(groupID, list, value)
+		}
+		if err != nil {
+			logger.Warningf("Error updating %s list of group %d: %v", list, groupID, err)
+		}
+		logger.Infof("%s %s %q in group %d by %d: changed=%v", list, args[0], value, groupID, query.From.ID, changed)
+
+		key := "group_list_" + args[0] + "ed"
+		if args[0] == "del" {
+			key = "group_list_deleted"
+		}
+		if !changed {
+			key += "_unchanged"
+		}
+		text = fmt.Sprintf(models.GetTranslation(language, key), html.EscapeString(value))
+
+	default:
+		text = formatGroupList(groupID, list, language)
+	}
+
+	return sendHTMLReply(bot, message.Chat.ID, text)
+}
+
+// formatGroupList renders the values of a group's list
+func formatGroupList(groupID int64, list string, language string) string {
+	values := service.GetGroupList(groupID, list)
+	if len(values) == 0 {
+		return fmt.Sprintf(models.GetTranslation(language, "group_list_empty"), list)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(models.GetTranslation(language, "group_list_title"), list))
+	for _, value := range values {
+		sb.WriteString(fmt.Sprintf("\n<code>%s</code>", html.EscapeString(value)))
+	}
+	return sb.String()
+}
@@ -15,6 +15,7 @@ import (
 	"tg-antispam/internal/crash"
 	"tg-antispam/internal/logger"
 	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
 	"tg-antispam/internal/service"
 	"tg-antispam/internal/storage"
 )
@@ -99,6 +100,11 @@ func handlePrivateMessage(bot *telego.Bot, message telego.Message) error {
 		// If not an unban request, continue with normal processing
 	}
 
+	// Forwarded messages of admins in training mode label the spam classifier
+	if handleTrainingMessage(bot, message) {
+		return nil
+	}
+
 	// Check for pending math verification answer
 	if err := HandleMathVerification(bot, message); err != nil {
 		logger.Warningf("Error handling math verification: %v", err)
@@ -121,66 +127,85 @@ func handleGroupMessage(bot *telego.Bot, message telego.Message) error {
 		return nil
 	}
 
-	text := ""
-	// @TODO: more rules
-	if strings.Contains(message.Text, "https://t.me/") || (strings.Contains(message.Text, "@") && !strings.HasPrefix(message.Text, "/")) {
-		text = message.Text
-	} else if message.ForwardOrigin != nil {
-		text = message.Caption
-		time.Sleep(3 * time.Second)
-	} else if message.Quote != nil {
-		text = message.Quote.Text
-		time.Sleep(3 * time.Second)
+	// Admins are not scanned
+	if isUserAdmin(bot, message.Chat.ID, message.From.ID) {
+		return nil
 	}
 
-	// Check if the user is pending
-	if _, ok := pendingUsers[message.From.ID]; ok {
-		logger.Infof("User %d is pending, delete message: %s", message.From.ID, text)
-		DeleteMessageWithRetry(bot, message.Chat.ID, message.MessageID)
-		return nil
+	// 消息检查流水线: 提取文本后依次运行群组启用的检测规则
+	subject := &rules.Subject{
+		Bot:     bot,
+		Group:   groupInfo,
+		User:    *message.From,
+		Message: &message,
+		Event:   rules.EventMessage,
+	}
+	// Every group feeds the cross-group duplicate index, groups without duplicate limits included
+	subject.RecordDuplicates()
+	decision := rules.Evaluate(subject)
+	text := subject.Text()
+	// Messages removed as spam teach the local classifier
+	if decision.Verdict != rules.VerdictPass && text != "" && learnsSpam(decision.Reason) {
+		service.TrainBayes(text, true)
 	}
+	applyMessageDecision(bot, groupInfo, message, decision, text)
+	return nil
+}
 
-	key := fmt.Sprintf("%d-%d", message.From.ID, message.Chat.ID)
-	if recentUser, ok := recentUsers[key]; ok {
-		if time.Since(recentUser) < 10*time.Second {
-			logger.Infof("User %d join group less than 10 seconds, delete message: %s", message.From.ID, text)
-			DeleteMessageWithRetry(bot, message.Chat.ID, message.MessageID)
-			return nil
-		}
+// applyMessageDecision deletes a message flagged by the pipeline and acts on its sender as the decision says
+func applyMessageDecision(bot *telego.Bot, groupInfo *models.GroupInfo, message telego.Message, decision rules.Decision, text string) {
+	switch decision.Verdict {
+	case rules.VerdictDelete:
+		logger.Infof("User %d message deleted (%s), text: %s", message.From.ID, decision.Evidence, text)
+		deleteUserMessage(bot, message, decision, text)
+	case rules.VerdictMute:
+		logger.Infof("User %d muted (%s), text: %s", message.From.ID, decision.Evidence, text)
+		DeleteMessageWithRetry(bot, message.Chat.ID, message.MessageID)
+		muteMessageSender(bot, groupInfo, *message.From, decision, text)
+	case rules.VerdictChallenge, rules.VerdictRestrict, rules.VerdictBan:
+		logger.Infof("suspicious message text: %s, delete and %s user: %d", text, decision.Verdict, message.From.ID)
+		DeleteMessageWithRetry(bot, message.Chat.ID, message.MessageID)
+		restrictMessageSender(bot, message.Chat.ID, *message.From, decision, text)
 	}
+	// A campaign crossing the duplicate limits is removed from every group it was posted in
+	if result, ok := decision.Result(rules.DuplicateRuleID); ok {
+		purgeDuplicateCopies(bot, message, result, text)
+	}
+}
 
-	//// CAS and AI check does't work well, so we disable it for now
-	// shouldRestrict := false
-	// reason := ""
-	// cfg := config.Get()
-	// if text != "" {
-	// 	logger.Infof("suspicious message: %s, request cas or ai check", text)
-	// 	shouldRestrict, reason = CasRequest(message.From.ID)
-
-	// 	if !shouldRestrict && groupInfo.EnableAicheck {
-	// 		if cfg.AiApi.GeminiApiKey != "" {
-	// 			isSpam, err := ClassifyWithGemini(cfg.AiApi.GeminiApiKey, cfg.AiApi.GeminiModel, text)
-	// 			if err != nil {
-	// 				logger.Warningf("Error classifying message with Gemini: %v", err)
-	// 			} else if isSpam {
-	// 				shouldRestrict = true
-	// 				reason = "reason_ai_spam"
-	// 			}
-	// 			logger.Infof("AI check message: %s, result: %t", text, isSpam)
-	// 		}
-	// 	}
-	// }
-
-	// if shouldRestrict {
-	// 	logger.Infof("suspicious message text: %s, delete and restrict user: %d", text, message.From.ID)
-	// 	bot.DeleteMessage(context.Background(), &telego.DeleteMessageParams{
-	// 		ChatID:    telego.ChatID{ID: message.Chat.ID},
-	// 		MessageID: message.MessageID,
-	// 	})
-	// 	restrictUser(bot, message.Chat.ID, *message.From, reason)
-	// }
+// deleteUserMessage deletes a message flagged by the pipeline and records the decision
+func deleteUserMessage(bot *telego.Bot, message telego.Message, decision rules.Decision, text string) {
+	service.CreateBanRecord(&models.BanRecord{
+		GroupID:     message.Chat.ID,
+		UserID:      message.From.ID,
+		Reason:      decision.Reason,
+		Action:      "delete",
+		Score:       decision.Score,
+		ScoreDetail: decision.Breakdown(),
+		MessageText: text,
+		Edited:      decision.Event == rules.EventEdit,
+	})
+	DeleteMessageWithRetry(bot, message.Chat.ID, message.MessageID)
+}
 
-	return nil
+// muteMessageSender mutes the user for the group's mute duration and records the decision.
+// A mute lifts itself, so the record never counts as an active ban.
+func muteMessageSender(bot *telego.Bot, groupInfo *models.GroupInfo, user telego.User, decision rules.Decision, messageText string) {
+	duration := rules.FloodMuteDuration(groupInfo)
+	service.CreateBanRecord(&models.BanRecord{
+		GroupID:     groupInfo.GroupID,
+		UserID:      user.ID,
+		Reason:      decision.Reason,
+		Action:      "mute",
+		Score:       decision.Score,
+		ScoreDetail: decision.Breakdown(),
+		MessageText: messageText,
+		Edited:      decision.Event == rules.EventEdit,
+	})
+	MuteUser(bot, groupInfo.GroupID, user.ID, duration)
+	if groupInfo.EnableNotification {
+		NotifyAdmin(bot, groupInfo.GroupID, user, decision)
+	}
 }
 
 // handleChatMemberUpdate processes updates to chat members
@@ -198,6 +223,11 @@ func handleChatMemberUpdate(bot *telego.Bot, update telego.Update) error {
 		return nil
 	}
 
+	// Promotions and demotions change the cached administrator list
+	if service.IsAdminStatus(update.ChatMember.OldChatMember) || service.IsAdminStatus(update.ChatMember.NewChatMember) {
+		service.InvalidateChatAdministrators(chatId)
+	}
+
 	fromUser := update.ChatMember.From
 	// Skip updates from self or admins
 	if fromUser.ID == botID || (!fromUser.IsBot && isUserAdmin(bot, chatId, fromUser.ID)) {
@@ -245,18 +275,29 @@ func checkRestrictedUser(bot *telego.Bot, chatId int64, newChatMember telego.Cha
 		if !fromUser.IsBot && newChatMember.MemberStatus() == telego.MemberStatusMember {
 			if _, ok := pendingUsers[user.ID]; !ok {
 				groupInfo := service.GetGroupInfo(bot, chatId, false)
+
+				// Admin impersonators are restricted right away instead of waiting for the join bot
+				decision := rules.EvaluateRule(rules.ImpersonationRuleID, &rules.Subject{
+					Bot:   bot,
+					Group: groupInfo,
+					User:  user,
+					Event: rules.EventJoin,
+				})
+				if decision.Verdict >= rules.VerdictRestrict {
+					restrictUser(bot, chatId, user, decision)
+					return nil
+				}
+
 				waitSec := groupInfo.WaitSec
 				if waitSec <= 0 {
-					reason := "reason_join_group"
-					restrictUser(bot, chatId, user, reason)
+					restrictUser(bot, chatId, user, rules.NewDecision(rules.VerdictRestrict, "reason_join_group"))
 				} else {
 					pendingUsers[user.ID] = chatId
 					userCopy := user // 创建副本避免闭包问题
 					crash.SafeGoroutine(fmt.Sprintf("pending-user-check-%d-%d", chatId, userCopy.ID), func() {
 						time.Sleep(time.Duration(waitSec) * time.Second)
 						if _, ok := pendingUsers[userCopy.ID]; ok {
-							reason := "reason_join_group"
-							restrictUser(bot, chatId, userCopy, reason)
+							restrictUser(bot, chatId, userCopy, rules.NewDecision(rules.VerdictRestrict, "reason_join_group"))
 						}
 					})
 				}
@@ -279,20 +320,29 @@ func checkRestrictedUser(bot *telego.Bot, chatId int64, newChatMember telego.Cha
 
 		// Check if user should be restricted
 		groupInfo := service.GetGroupInfo(bot, chatId, false)
-		shouldRestrict, reason := ShouldRestrictUser(bot, groupInfo, user)
-		if !shouldRestrict && groupInfo.EnableCAS {
-			shouldRestrict, reason = CasRequest(user.ID)
+		decision := rules.Evaluate(&rules.Subject{
+			Bot:   bot,
+			Group: groupInfo,
+			User:  user,
+			Event: rules.EventJoin,
+		})
+
+		// Low risk users still fall under the join policy, keep the score for the record
+		if decision.Verdict < rules.VerdictChallenge {
+			decision.Verdict = rules.VerdictRestrict
+			decision.Reason = "reason_join_group"
 		}
-
-		if !shouldRestrict {
-			reason = "reason_join_group"
-		}
-		restrictUser(bot, chatId, user, reason)
+		restrictUser(bot, chatId, user, decision)
 	}
 	return nil
 }
 
-func restrictUser(bot *telego.Bot, chatId int64, user telego.User, reason string) {
+func restrictUser(bot *telego.Bot, chatId int64, user telego.User, decision rules.Decision) {
+	restrictMessageSender(bot, chatId, user, decision, "")
+}
+
+// restrictMessageSender restricts or bans the user and keeps the offending message text in the ban record
+func restrictMessageSender(bot *telego.Bot, chatId int64, user telego.User, decision rules.Decision, messageText string) {
 	restrictMutex.Lock()
 	defer restrictMutex.Unlock()
 
@@ -305,19 +355,48 @@ func restrictUser(bot *telego.Bot, chatId int64, user telego.User, reason string
 		return
 	}
 
-	logger.Infof("Restricting user: %s, reason: %s", user.FirstName, reason)
-	service.CreateBanRecord(chatId, user.ID, reason)
-	userCopy := user     // 创建副本避免闭包问题
-	// reasonCopy := reason // 创建副本避免闭包问题
+	action := "restrict"
+	switch decision.Verdict {
+	case rules.VerdictBan:
+		action = "ban"
+	case rules.VerdictChallenge:
+		action = "challenge"
+	}
+	accountCreatedAt := rules.EstimateAccountCreation(user.ID)
+
+	logger.Infof("Restricting user: %s, action: %s, reason: %s, score: %d (%s)", user.FirstName, action, decision.Reason, decision.Score, decision.Breakdown())
+	service.CreateBanRecord(&models.BanRecord{
+		GroupID:          chatId,
+		UserID:           user.ID,
+		Reason:           decision.Reason,
+		Action:           action,
+		Score:            decision.Score,
+		ScoreDetail:      decision.Breakdown(),
+		AccountCreatedAt: &accountCreatedAt,
+		MessageText:      messageText,
+		Edited:           decision.Event == rules.EventEdit,
+	})
+	userCopy := user // 创建副本避免闭包问题
 	crash.SafeGoroutine(fmt.Sprintf("restrict-user-%d-%d", chatId, userCopy.ID), func() {
-		RestrictUser(bot, chatId, userCopy.ID)
+		if action == "ban" {
+			BanUser(bot, chatId, userCopy.ID)
+		} else {
+			RestrictUser(bot, chatId, userCopy.ID)
+		}
 		delete(pendingUsers, user.ID)
-		// // Send warning only if notifications are enabled
-		// groupInfo := service.GetGroupInfo(bot, chatId, false)
-		// if groupInfo.EnableNotification {
-		// 	NotifyAdmin(bot, groupInfo.GroupID, userCopy, reasonCopy)
-		// }
-		// NotifyUserInGroup(bot, groupInfo.GroupID, userCopy)
+
+		// Only notify admins about restrictions caused by spam signals, not the plain join policy
+		if decision.Reason == "reason_join_group" {
+			return
+		}
+		groupInfo := service.GetGroupInfo(bot, chatId, false)
+		if groupInfo != nil && (groupInfo.EnableNotification || isHighPriority(decision)) {
+			NotifyAdmin(bot, groupInfo.GroupID, userCopy, decision)
+		}
+		// Challenged users get the self-unban instructions in the group
+		if action == "challenge" {
+			NotifyUserInGroup(bot, chatId, userCopy)
+		}
 	})
 }
 
@@ -382,6 +461,7 @@ func handleMyChatMemberUpdate(bot *telego.Bot, update telego.Update) error {
 			}
 		} else if chatType == "group" || chatType == "supergroup" {
 			// 处理群组/超级群中的机器人状态更新
+			service.InvalidateChatAdministrators(chatID)
 			logger.Infof("Bot status change detected in %s %d (Title: %s). Old status: %s, New status: %s", chatType, chatID, update.MyChatMember.Chat.Title, update.MyChatMember.OldChatMember.MemberStatus(), update.MyChatMember.NewChatMember.MemberStatus())
 
 			// 获取或创建群组信息 (create=true 会从DB加载或创建新记录)
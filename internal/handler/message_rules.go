@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tg-antispam/internal/ai"
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+)
+
+// registerMessageRules registers the rules that depend on handler state
+func registerMessageRules() {
+	rules.Register(pendingUserRule{})
+	rules.Register(recentJoinRule{})
+	rules.Register(aiMessageRule{})
+	rules.Register(aiProfileRule{})
+}
+
+// pendingUserRule deletes messages from users still waiting for the join check
+type pendingUserRule struct{}
+
+func (pendingUserRule) ID() string                           { return "pending_user" }
+func (pendingUserRule) Order() int                           { return 10 }
+func (pendingUserRule) Events() rules.Event                  { return rules.EventMessage }
+func (pendingUserRule) Enabled(group *models.GroupInfo) bool { return true }
+
+func (r pendingUserRule) Evaluate(subject *rules.Subject) rules.Result {
+	if _, ok := pendingUsers[subject.User.ID]; !ok {
+		return rules.Result{RuleID: r.ID()}
+	}
+	return rules.Result{
+		RuleID:   r.ID(),
+		Verdict:  rules.VerdictDelete,
+		Reason:   "reason_join_group",
+		Evidence: fmt.Sprintf("user %d is pending", subject.User.ID),
+	}
+}
+
+// recentJoinRule deletes messages sent within 10 seconds after joining
+type recentJoinRule struct{}
+
+func (recentJoinRule) ID() string                           { return "recent_join" }
+func (recentJoinRule) Order() int                           { return 20 }
+func (recentJoinRule) Events() rules.Event                  { return rules.EventMessage }
+func (recentJoinRule) Enabled(group *models.GroupInfo) bool { return true }
+
+func (r recentJoinRule) Evaluate(subject *rules.Subject) rules.Result {
+	key := fmt.Sprintf("%d-%d", subject.User.ID, subject.Group.GroupID)
+	joinTime, ok := recentUsers[key]
+	if !ok || time.Since(joinTime) >= 10*time.Second {
+		return rules.Result{RuleID: r.ID()}
+	}
+	return rules.Result{
+		RuleID:   r.ID(),
+		Verdict:  rules.VerdictDelete,
+		Reason:   "reason_join_group",
+		Evidence: fmt.Sprintf("joined %s ago", time.Since(joinTime).Round(time.Second)),
+	}
+}
+
+// aiSpamConfidence is the confidence from which a spam label of the AI model is acted on
+const aiSpamConfidence = 0.7
+
+// aiExampleCount is the number of spam and of ham examples from the group's history added to the prompt
+const aiExampleCount = 5
+
+// groupPrompt builds the AI prompt of a group from its policy and the recent messages admins judged.
+// Only admin feedback is used so the model is never shown its own unreviewed verdicts as ground truth,
+// and spam removed for non-content reasons like flooding is left out.
+func groupPrompt(group *models.GroupInfo) ai.Prompt {
+	return ai.Prompt{
+		Policy:       group.AiPolicy,
+		SpamExamples: service.GetMessageExamples(group.GroupID, true, aiExampleCount, rules.NonContentReasons()),
+		HamExamples:  service.GetMessageExamples(group.GroupID, false, aiExampleCount, nil),
+	}
+}
+
+// aiMessageRule classifies suspicious messages with the AI model
+type aiMessageRule struct{}
+
+func (aiMessageRule) ID() string          { return "message_ai" }
+func (aiMessageRule) Order() int          { return 70 }
+func (aiMessageRule) Events() rules.Event { return rules.EventMessage | rules.EventEdit }
+func (aiMessageRule) Costly() bool        { return true }
+
+func (aiMessageRule) Enabled(group *models.GroupInfo) bool {
+	return group.EnableMessageScan && group.EnableAicheck
+}
+
+func (r aiMessageRule) Evaluate(subject *rules.Subject) rules.Result {
+	classifier := ai.Current()
+	text := subject.Text()
+	if classifier == nil || !rules.IsSuspiciousText(text) {
+		return rules.Result{RuleID: r.ID()}
+	}
+
+	buildPrompt := func() ai.Prompt { return groupPrompt(subject.Group) }
+	verdict, err := ai.Classify(context.Background(), subject.Group.GroupID, subject.Group.AiPolicy, buildPrompt, text)
+	if errors.Is(err, ai.ErrBudgetExceeded) || errors.Is(err, ai.ErrRateLimited) {
+		logger.Infof("Skipping AI check in group %d: %v", subject.Group.GroupID, err)
+		return rules.Result{RuleID: r.ID()}
+	}
+	if err != nil {
+		logger.Warningf("Error classifying message with %s: %v", classifier.Name(), err)
+		return rules.Result{RuleID: r.ID()}
+	}
+	logger.Infof("AI check message: %s, result: %+v", text, verdict)
+	if !verdict.IsSpam() || verdict.Confidence < aiSpamConfidence {
+		return rules.Result{RuleID: r.ID()}
+	}
+	return rules.Result{
+		RuleID:   r.ID(),
+		Verdict:  rules.MessageVerdict(subject.Group),
+		Reason:   rules.ReasonAISpam,
+		Evidence: fmt.Sprintf("%s %.2f: %s", classifier.Name(), verdict.Confidence, verdict.Rationale),
+	}
+}
+
+// aiProfileRuleID identifies the AI profile check, its evidence is the model's rationale
+const aiProfileRuleID = "profile_ai"
+
+// aiProfileRule classifies the name, username and bio of joining users with the AI model
+type aiProfileRule struct{}
+
+func (aiProfileRule) ID() string                           { return aiProfileRuleID }
+func (aiProfileRule) Order() int                           { return 90 }
+func (aiProfileRule) Events() rules.Event                  { return rules.EventJoin }
+func (aiProfileRule) Enabled(group *models.GroupInfo) bool { return group.EnableAiProfile }
+func (aiProfileRule) Costly() bool                         { return true }
+
+func (r aiProfileRule) Evaluate(subject *rules.Subject) rules.Result {
+	classifier := ai.Current()
+	if classifier == nil {
+		return rules.Result{RuleID: r.ID()}
+	}
+
+	bio, err := subject.Bio()
+	if err != nil {
+		logger.Warningf("Error getting chat info for user %d: %v", subject.User.ID, err)
+	}
+	text := profileText(subject.User.FirstName+" "+subject.User.LastName, subject.User.Username, bio)
+
+	buildPrompt := func() ai.Prompt { return ai.Prompt{Policy: subject.Group.AiPolicy, Profile: true} }
+	verdict, err := ai.Classify(context.Background(), subject.Group.GroupID, subject.Group.AiPolicy, buildPrompt, text)
+	if errors.Is(err, ai.ErrBudgetExceeded) || errors.Is(err, ai.ErrRateLimited) {
+		logger.Infof("Skipping AI profile check in group %d: %v", subject.Group.GroupID, err)
+		return rules.Result{RuleID: r.ID()}
+	}
+	if err != nil {
+		logger.Warningf("Error classifying profile of user %d with %s: %v", subject.User.ID, classifier.Name(), err)
+		return rules.Result{RuleID: r.ID()}
+	}
+	logger.Infof("AI check profile of user %d: %q, result: %+v", subject.User.ID, text, verdict)
+	if !verdict.IsSpam() || verdict.Confidence < aiSpamConfidence {
+		return rules.Result{RuleID: r.ID()}
+	}
+	return rules.Result{
+		RuleID:   r.ID(),
+		Score:    rules.ScoreAISpam,
+		Reason:   "reason_ai_profile",
+		Evidence: fmt.Sprintf("%s %.2f: %s", classifier.Name(), verdict.Confidence, verdict.Rationale),
+	}
+}
+
+// profileText renders the normalized profile the AI model judges, one field per line
+func profileText(name, username, bio string) string {
+	text := "Name: " + strings.TrimSpace(normalize.Skeleton(name))
+	if username != "" {
+		text += "\nUsername: @" + username
+	}
+	if bio = strings.TrimSpace(normalize.Skeleton(bio)); bio != "" {
+		text += "\nBio: " + bio
+	}
+	return text
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// numericSetting is a group setting picked from a keyboard of numbers, its callback data is key:value:groupID
+// and its texts are the select_<key> prompt and the <key>_updated confirmation
+type numericSetting struct {
+	// options are the selectable values, the callback only accepts these
+	options []int
+	// label is the button text of a value
+	label func(language string, value int) string
+	// validate returns the message shown to the admin when the value conflicts with the group's other settings, "" to accept it
+	validate func(language string, group *models.GroupInfo, value int) string
+	// set stores the value in the group
+	set func(group *models.GroupInfo, value int)
+}
+
+// numericSettings are the numeric group settings by callback key
+var numericSettings = map[string]numericSetting{
+	"restrict_score": {
+		options:  []int{20, 30, 50, 70},
+		label:    numberLabel("", "", ""),
+		validate: validateRestrictScore,
+		set:      func(group *models.GroupInfo, value int) { group.RestrictScore = value },
+	},
+	"ban_score": {
+		// 0 disables banning
+		options:  []int{0, 60, 100, 150},
+		label:    numberLabel("", "", "never"),
+		validate: validateBanScore,
+		set:      func(group *models.GroupInfo, value int) { group.BanScore = value },
+	},
+	"emoji_min_count": {
+		options: []int{1, 2, 3, 5},
+		label:   numberLabel("", "", ""),
+		set:     func(group *models.GroupInfo, value int) { group.EmojiMinCount = value },
+	},
+	"emoji_ratio": {
+		// 0 disables the ratio check
+		options: []int{0, 25, 50, 100},
+		label:   numberLabel("%", "", "disabled"),
+		set:     func(group *models.GroupInfo, value int) { group.EmojiRatio = value },
+	},
+	"username_threshold": {
+		// random username probabilities in percent
+		options: []int{50, 60, 70, 80, 90},
+		label:   numberLabel("%", "", ""),
+		set:     func(group *models.GroupInfo, value int) { group.RandomUsernameThreshold = value },
+	},
+	"min_account_age": {
+		// days, 0 disables the check
+		options: []int{0, 7, 30, 90, 180},
+		label:   numberLabel("", "days", "disabled"),
+		set:     func(group *models.GroupInfo, value int) { group.MinAccountAgeDays = value },
+	},
+	"edit_window": {
+		// minutes, 0 allows late edits
+		options: []int{0, 5, 15, 60, 1440},
+		label:   numberLabel("", "minutes", "disabled"),
+		set:     func(group *models.GroupInfo, value int) { group.EditWindowMinutes = value },
+	},
+}
+
+// numberLabel formats a value with an optional suffix and translated unit, zero shows the zeroKey translation when given
+func numberLabel(suffix, unitKey, zeroKey string) func(language string, value int) string {
+	return func(language string, value int) string {
+		if value == 0 && zeroKey != "" {
+			return models.GetTranslation(language, zeroKey)
+		}
+		text := strconv.Itoa(value) + suffix
+		if unitKey != "" {
+			text += " " + models.GetTranslation(language, unitKey)
+		}
+		return text
+	}
+}
+
+// validateRestrictScore keeps the restrict threshold below a ban threshold that is in use
+func validateRestrictScore(language string, group *models.GroupInfo, value int) string {
+	if group.BanScore > 0 && value >= group.BanScore {
+		return fmt.Sprintf(models.GetTranslation(language, "restrict_score_not_below_ban"), group.BanScore)
+	}
+	return ""
+}
+
+// validateBanScore keeps the ban threshold above the restrict threshold, 0 never bans and is always accepted
+func validateBanScore(language string, group *models.GroupInfo, value int) string {
+	if value > 0 && value <= group.RestrictScore {
+		return fmt.Sprintf(models.GetTranslation(language, "ban_score_not_above_restrict"), group.RestrictScore)
+	}
+	return ""
+}
+
+// isNumericSettingCallback reports whether the callback data selects a numeric setting value
+func isNumericSettingCallback(data string) bool {
+	key, _, ok := strings.Cut(data, ":")
+	if !ok {
+		return false
+	}
+	_, ok = numericSettings[key]
+	return ok
+}
+
+// handleNumericSettingCallback processes numeric setting selection callbacks
+func handleNumericSettingCallback(bot *telego.Bot, query telego.CallbackQuery) error {
+	// 解析回调数据: key:value:groupID
+	parts := strings.Split(query.Data, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	key := parts[0]
+	setting := numericSettings[key]
+	value, err := strconv.Atoi(parts[1])
+	if err != nil || !slices.Contains(setting.options, value) {
+		logger.Warningf("Invalid %s in callback: %s", key, parts[1])
+		return nil
+	}
+
+	groupID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		logger.Warningf("Invalid group ID in callback: %s", parts[2])
+		return nil
+	}
+
+	// 获取群组信息
+	groupInfo := service.GetGroupInfo(bot, groupID, false)
+	if groupInfo == nil {
+		logger.Warningf("Group info not found: %d", groupID)
+		return nil
+	}
+
+	// 检查用户是否有权限
+	if groupInfo.AdminID != query.From.ID {
+		isAdmin, err := checkAdminQuery(bot, query, groupID)
+		if !isAdmin {
+			return err
+		}
+	}
+
+	// 获取语言
+	language := GetBotQueryLang(bot, &query)
+
+	// 检查与其他设置是否冲突
+	if setting.validate != nil {
+		if rejection := setting.validate(language, groupInfo, value); rejection != "" {
+			err = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            rejection,
+				ShowAlert:       true,
+			})
+			if err != nil {
+				logger.Warningf("Error answering callback query: %v", err)
+			}
+			return nil
+		}
+	}
+
+	// 更新设置
+	setting.set(groupInfo, value)
+	service.UpdateGroupInfo(groupInfo)
+
+	// 通知用户设置已更新
+	err = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            fmt.Sprintf(models.GetTranslation(language, key+"_updated"), value),
+	})
+	if err != nil {
+		logger.Warningf("Error answering callback query: %v", err)
+	}
+
+	// 更新设置消息
+	if query.Message != nil {
+		if message, ok := query.Message.(*telego.Message); ok {
+			return showGroupSettings(bot, *message, groupID)
+		}
+	}
+
+	return nil
+}
+
+// showNumericSelection displays the options of a numeric setting
+func showNumericSelection(bot *telego.Bot, query telego.CallbackQuery, groupID int64, language string, key string) error {
+	setting, ok := numericSettings[key]
+	if !ok {
+		return nil
+	}
+
+	// 创建选择键盘
+	var keyboard [][]telego.InlineKeyboardButton
+	for _, value := range setting.options {
+		keyboard = append(keyboard, []telego.InlineKeyboardButton{
+			{
+				Text:         setting.label(language, value),
+				CallbackData: fmt.Sprintf("%s:%d:%d", key, value, groupID),
+			},
+		})
+	}
+
+	message, ok := query.Message.(*telego.Message)
+	if !ok {
+		logger.Warningf("Unexpected message type in %s selection: %T", key, query.Message)
+		return nil
+	}
+
+	_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: message.Chat.ID},
+		Text:        models.GetTranslation(language, "select_"+key),
+		ParseMode:   "HTML",
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: keyboard},
+	})
+	if err != nil {
+		logger.Warningf("Error sending %s selection message: %v", key, err)
+	}
+	return err
+}
@@ -2,11 +2,8 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"regexp"
-	"strconv"
+	"html"
 	"strings"
 	"sync"
 	"time"
@@ -16,17 +13,10 @@ import (
 	"tg-antispam/internal/crash"
 	"tg-antispam/internal/logger"
 	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
 	"tg-antispam/internal/service"
 )
 
-var (
-	// Compiled regular expressions
-	emojiRegex  = regexp.MustCompile(`[\x{1F600}-\x{1F64F}|\x{1F300}-\x{1F5FF}|\x{1F680}-\x{1F6FF}|\x{1F700}-\x{1F77F}|\x{1F780}-\x{1F7FF}|\x{1F800}-\x{1F8FF}|\x{1F900}-\x{1F9FF}|\x{1FA00}-\x{1FA6F}|\x{1FA70}-\x{1FAFF}|\x{2600}-\x{26FF}|\x{2700}-\x{27BF}]`)
-	tgLinkRegex = regexp.MustCompile(`t\.me|@`)
-
-	CasRecords = models.NewUserActionManager(10)
-)
-
 // In-memory pending deletion (if DB is off)
 type inMemoryPendingMsg struct {
 	ChatID    int64
@@ -87,119 +77,50 @@ func DeleteAllPendingInMemoryMessages(bot *telego.Bot) {
 	}
 }
 
-// ShouldRestrictUser determines if a user should be restricted
-func ShouldRestrictUser(bot *telego.Bot, groupInfo *models.GroupInfo, user telego.User) (bool, string) {
-	if groupInfo.BanPremium && user.IsPremium {
-		return true, "reason_premium_user"
-	}
-
-	if groupInfo.BanEmojiName && HasEmoji(user.FirstName) {
-		return true, "reason_emoji_name"
-	}
-
-	if groupInfo.BanRandomUsername && user.Username != "" && IsRandomUsername(user.Username) {
-		return true, "reason_random_username"
-	}
-
-	if groupInfo.BanBioLink && HasLinksInBio(bot, user.ID) {
-		return true, "reason_bio_link"
-	}
-
-	return false, ""
-}
+// RestrictUser restricts a user in a chat
+func RestrictUser(bot *telego.Bot, chatID int64, userID int64) {
+	permissions := telego.ChatPermissions{}
 
-// CasRequest checks if a user is listed in the Combot Anti-Spam System (CAS)
-func CasRequest(userID int64) (bool, string) {
-	if CasRecords.Contains(userID) {
-		return false, ""
-	}
+	err := bot.RestrictChatMember(context.Background(), &telego.RestrictChatMemberParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		UserID:      userID,
+		Permissions: permissions,
+	})
 
-	// Make request to CAS API
-	casResp, err := http.Get("https://api.cas.chat/check?user_id=" + strconv.FormatInt(userID, 10))
 	if err != nil {
-		logger.Warningf("Error checking CAS for user %d: %v", userID, err)
-		return false, ""
-	}
-	defer casResp.Body.Close()
-
-	if casResp.StatusCode != 200 {
-		logger.Warningf("CAS API returned status code %d for user %d", casResp.StatusCode, userID)
-		return false, ""
-	}
-
-	// Parse response
-	var casResult struct {
-		Ok     bool `json:"ok"`
-		Result struct {
-			Offenses  int   `json:"offenses"`
-			TimeAdded int64 `json:"time_added"`
-		} `json:"result"`
-	}
-
-	if err := json.NewDecoder(casResp.Body).Decode(&casResult); err != nil {
-		logger.Warningf("Error decoding CAS response for user %d: %v", userID, err)
-		return false, ""
-	}
-
-	// Cache the result
-	if casResult.Ok {
-		CasRecords.Add(userID)
+		logger.Warningf("Error restricting user %d in chat %d: %v", userID, chatID, err)
+	} else {
+		logger.Infof("Successfully restricted user %d in chat %d", userID, chatID)
 	}
-
-	return casResult.Ok, "reason_cas"
 }
 
-// HasLinksInBio checks if a user has t.me links in their bio
-func HasLinksInBio(bot *telego.Bot, userID int64) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	chat, err := bot.GetChat(ctx, &telego.GetChatParams{
-		ChatID: telego.ChatID{ID: userID},
+// MuteUser takes all send permissions of a user in a chat for the given duration
+func MuteUser(bot *telego.Bot, chatID int64, userID int64, duration time.Duration) {
+	err := bot.RestrictChatMember(context.Background(), &telego.RestrictChatMemberParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		UserID:      userID,
+		Permissions: telego.ChatPermissions{},
+		UntilDate:   time.Now().Add(duration).Unix(),
 	})
 
 	if err != nil {
-		logger.Warningf("Error getting chat info for user %d: %v", userID, err)
-		return false
-	}
-
-	return chat.Bio != "" && tgLinkRegex.MatchString(chat.Bio)
-}
-
-// HasEmoji checks if a string contains emoji
-func HasEmoji(s string) bool {
-	return emojiRegex.MatchString(s)
-}
-
-// IsRandomUsername checks if a username appears to be randomly generated
-func IsRandomUsername(username string) bool {
-	if len(username) < 5 {
-		return false
-	}
-
-	consonantsRegex := regexp.MustCompile(`[bcdfghjklmnpqrstvwxyz]{5}`)
-	if consonantsRegex.MatchString(strings.ToLower(username)) {
-		return true
+		logger.Warningf("Error muting user %d in chat %d: %v", userID, chatID, err)
+	} else {
+		logger.Infof("Successfully muted user %d in chat %d for %s", userID, chatID, duration)
 	}
-
-	digitsRegex := regexp.MustCompile(`\d{7}`)
-	return digitsRegex.MatchString(username)
 }
 
-// RestrictUser restricts a user in a chat
-func RestrictUser(bot *telego.Bot, chatID int64, userID int64) {
-	permissions := telego.ChatPermissions{}
-
-	err := bot.RestrictChatMember(context.Background(), &telego.RestrictChatMemberParams{
-		ChatID:      telego.ChatID{ID: chatID},
-		UserID:      userID,
-		Permissions: permissions,
+// BanUser bans a user from a chat
+func BanUser(bot *telego.Bot, chatID int64, userID int64) {
+	err := bot.BanChatMember(context.Background(), &telego.BanChatMemberParams{
+		ChatID: telego.ChatID{ID: chatID},
+		UserID: userID,
 	})
 
 	if err != nil {
-		logger.Warningf("Error restricting user %d in chat %d: %v", userID, chatID, err)
+		logger.Warningf("Error banning user %d in chat %d: %v", userID, chatID, err)
 	} else {
-		logger.Infof("Successfully restricted user %d in chat %d", userID, chatID)
+		logger.Infof("Successfully banned user %d in chat %d", userID, chatID)
 	}
 }
 
@@ -219,7 +140,8 @@ func GetLinkedUserName(user telego.User) string {
 	return fmt.Sprintf("<a href=\"tg://user?id=%d\">%s</a>", user.ID, displayName)
 }
 
-// DeleteMessageWithRetry attempts to delete a message, with one retry after 10 seconds if the first attempt fails
+// DeleteMessageWithRetry attempts to delete a message, with one retry after 10 seconds if the first attempt fails.
+// The retry is scheduled with a timer so the caller is not blocked.
 func DeleteMessageWithRetry(bot *telego.Bot, chatID int64, messageID int) {
 	err := bot.DeleteMessage(context.Background(), &telego.DeleteMessageParams{
 		ChatID:    telego.ChatID{ID: chatID},
@@ -229,18 +151,42 @@ func DeleteMessageWithRetry(bot *telego.Bot, chatID int64, messageID int) {
 	if err != nil {
 		logger.Warningf("Failed to delete message %d in chat %d: %v, will retry in 10s", messageID, chatID, err)
 
-		// Wait 10 seconds before retry
-		time.Sleep(10 * time.Second)
-
 		// Retry once
-		err = bot.DeleteMessage(context.Background(), &telego.DeleteMessageParams{
-			ChatID:    telego.ChatID{ID: chatID},
-			MessageID: messageID,
+		time.AfterFunc(10*time.Second, func() {
+			err := bot.DeleteMessage(context.Background(), &telego.DeleteMessageParams{
+				ChatID:    telego.ChatID{ID: chatID},
+				MessageID: messageID,
+			})
+			if err != nil {
+				logger.Warningf("Failed to delete message %d in chat %d after retry: %v", messageID, chatID, err)
+			}
 		})
 	}
 }
 
-func NotifyAdmin(bot *telego.Bot, groupID int64, user telego.User, reason string) {
+// formatScoreBreakdown renders the matched signals with their translated reasons
+func formatScoreBreakdown(language string, decision rules.Decision) string {
+	var parts []string
+	for _, result := range decision.Results {
+		if result.Score > 0 {
+			parts = append(parts, fmt.Sprintf("%s +%d", models.GetTranslation(language, result.Reason), result.Score))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isHighPriority reports whether a decision needs the admin's attention even with notifications disabled,
+// a weak impersonation match that only added score does not
+func isHighPriority(decision rules.Decision) bool {
+	for _, result := range decision.Results {
+		if result.RuleID == rules.ImpersonationRuleID && result.Verdict != rules.VerdictPass {
+			return true
+		}
+	}
+	return false
+}
+
+func NotifyAdmin(bot *telego.Bot, groupID int64, user telego.User, decision rules.Decision) {
 	groupInfo := service.GetGroupInfo(bot, groupID, false)
 	if groupInfo == nil {
 		return
@@ -257,13 +203,41 @@ func NotifyAdmin(bot *telego.Bot, groupID int64, user telego.User, reason string
 		return
 	}
 
+	restricted := fmt.Sprintf(models.GetTranslation(language, "warning_restricted"), userLink)
+	switch decision.Verdict {
+	case rules.VerdictBan:
+		restricted = fmt.Sprintf(models.GetTranslation(language, "warning_banned"), userLink)
+	case rules.VerdictMute:
+		restricted = fmt.Sprintf(models.GetTranslation(language, "warning_muted"), userLink, int(rules.FloodMuteDuration(groupInfo).Minutes()))
+	}
+
 	// Construct message with appropriate translation
 	message := fmt.Sprintf(
 		"%s\n%s\n%s",
 		fmt.Sprintf(models.GetTranslation(language, "warning_title"), linkedGroupName),
-		fmt.Sprintf(models.GetTranslation(language, "warning_restricted"), userLink),
-		fmt.Sprintf(models.GetTranslation(language, "warning_reason"), models.GetTranslation(language, reason)),
+		restricted,
+		fmt.Sprintf(models.GetTranslation(language, "warning_reason"), models.GetTranslation(language, decision.Reason)),
 	)
+	if decision.Event == rules.EventEdit {
+		message += "\n" + models.GetTranslation(language, "warning_edited")
+	}
+	if decision.Score > 0 {
+		message += "\n" + fmt.Sprintf(models.GetTranslation(language, "warning_score"), decision.Score, formatScoreBreakdown(language, decision))
+	}
+	accountCreatedAt := rules.EstimateAccountCreation(user.ID)
+	message += "\n" + fmt.Sprintf(models.GetTranslation(language, "warning_account_age"),
+		accountCreatedAt.Format("2006-01-02"), rules.EstimateAccountAgeDays(user.ID))
+	for _, result := range decision.Results {
+		if result.RuleID == aiProfileRuleID {
+			message += "\n" + fmt.Sprintf(models.GetTranslation(language, "warning_ai_rationale"), html.EscapeString(result.Evidence))
+		}
+	}
+	if isHighPriority(decision) {
+		message = models.GetTranslation(language, "warning_high_priority") + "\n" + message
+		if decision.Evidence != "" {
+			message += "\n" + fmt.Sprintf(models.GetTranslation(language, "warning_evidence"), html.EscapeString(decision.Evidence))
+		}
+	}
 
 	// Send notification to admin chat if it exists
 	if groupInfo.AdminID > 0 {
@@ -272,9 +246,14 @@ func NotifyAdmin(bot *telego.Bot, groupID int64, user telego.User, reason string
 			Text:         models.GetTranslation(groupInfo.Language, "warning_unban_button"),
 			CallbackData: fmt.Sprintf("unban:%d:%d", groupInfo.GroupID, user.ID),
 		}
+		// Block the user's profile photo as a spam avatar in this group
+		blockAvatarButton := telego.InlineKeyboardButton{
+			Text:         models.GetTranslation(groupInfo.Language, "warning_block_avatar_button"),
+			CallbackData: fmt.Sprintf("avatar:%d:%d", groupInfo.GroupID, user.ID),
+		}
 		adminMarkup := &telego.InlineKeyboardMarkup{
 			InlineKeyboard: [][]telego.InlineKeyboardButton{
-				{adminUnbanButton},
+				{adminUnbanButton, blockAvatarButton},
 			},
 		}
 
@@ -382,6 +361,16 @@ func UnrestrictUser(bot *telego.Bot, chatID int64, userID int64) {
 		permissions = *chatInfo.Permissions
 	}
 
+	// Lift a ban first in case the score reached the ban band, no-op for restricted members
+	err = bot.UnbanChatMember(context.Background(), &telego.UnbanChatMemberParams{
+		ChatID:       telego.ChatID{ID: chatID},
+		UserID:       userID,
+		OnlyIfBanned: true,
+	})
+	if err != nil {
+		logger.Warningf("Error unbanning user %d in chat %d: %v", userID, chatID, err)
+	}
+
 	err = bot.RestrictChatMember(context.Background(), &telego.RestrictChatMemberParams{
 		ChatID:      telego.ChatID{ID: chatID},
 		UserID:      userID,
@@ -8,10 +8,15 @@ import (
 	"github.com/mymmrac/telego"
 	th "github.com/mymmrac/telego/telegohandler"
 
+	"tg-antispam/internal/ai"
+	"tg-antispam/internal/cas"
 	"tg-antispam/internal/config"
 	"tg-antispam/internal/crash"
 	"tg-antispam/internal/logger"
+	"tg-antispam/internal/provider"
+	"tg-antispam/internal/rules"
 	"tg-antispam/internal/service"
+	"tg-antispam/internal/urlresolve"
 )
 
 var globalConfig *config.Config
@@ -34,6 +39,13 @@ func Initialize(cfg *config.Config) {
 	messageProcessingSemaphore = make(chan struct{}, maxConcurrentMessages)
 
 	ClearRecentUsers()
+	registerMessageRules()
+	rules.SetScoreOverrides(cfg.Antispam.RuleScores)
+	rules.SetDuplicateWindow(time.Duration(cfg.Antispam.DuplicateWindowMinutes) * time.Minute)
+	cas.Initialize(cfg.Cas)
+	provider.Initialize(cfg.BlocklistProviders)
+	ai.Initialize(cfg.AiApi)
+	urlresolve.Initialize(cfg.UrlResolve)
 }
 
 // SetupMessageHandlers configures all bot message and update handlers
@@ -47,6 +59,12 @@ func SetupMessageHandlers(bh *th.BotHandler, bot *telego.Bot) {
 		return nil
 	})
 
+	bh.HandleEditedMessage(func(ctx *th.Context, message telego.Message) error {
+		// 异步处理编辑的消息
+		processMessageAsync(bot, message, "edited_message")
+		return nil
+	})
+
 	bh.HandleChannelPost(func(ctx *th.Context, message telego.Message) error {
 		// 异步处理频道消息
 		processMessageAsync(bot, message, "channel_post")
@@ -104,6 +122,17 @@ func processMessageAsync(bot *telego.Bot, message telego.Message, msgType string
 				done <- true
 			}()
 
+			// 编辑的消息只做内容检查，不执行命令
+			if msgType == "edited_message" {
+				err = handleEditedMessage(bot, message)
+				if err != nil {
+					incrementCounter(&totalErrors)
+				} else {
+					incrementCounter(&totalMessagesProcessed)
+				}
+				return
+			}
+
 			// 处理命令
 			ok, cmdErr := HandleCommand(bot, message)
 			if ok {
@@ -3,10 +3,13 @@ package handler
 import (
 	"fmt"
 	"runtime"
+	"strings"
 	"sync/atomic"
 	"time"
 
+	"tg-antispam/internal/ai"
 	"tg-antispam/internal/logger"
+	"tg-antispam/internal/storage"
 )
 
 // 统计信息
@@ -16,6 +19,7 @@ var (
 	totalCallbackQueries   int64
 	totalErrors            int64
 	totalTimeouts          int64
+	totalEditSpam          int64
 	startTime              = time.Now()
 )
 
@@ -31,6 +35,7 @@ func GetProcessingStats() map[string]interface{} {
 
 	activeHandlers := GetActiveHandlersCount()
 	uptime := time.Since(startTime)
+	_, aiUsage := ai.UsageReport()
 
 	return map[string]interface{}{
 		"uptime_seconds":            int64(uptime.Seconds()),
@@ -39,6 +44,7 @@ func GetProcessingStats() map[string]interface{} {
 		"total_callback_queries":    atomic.LoadInt64(&totalCallbackQueries),
 		"total_errors":              atomic.LoadInt64(&totalErrors),
 		"total_timeouts":            atomic.LoadInt64(&totalTimeouts),
+		"total_edit_spam":           atomic.LoadInt64(&totalEditSpam),
 		"active_handlers":           activeHandlers,
 		"max_concurrent_messages":   cap(messageProcessingSemaphore),
 		"memory_usage_mb":           bToMb(m.Alloc),
@@ -46,6 +52,10 @@ func GetProcessingStats() map[string]interface{} {
 		"sys_memory_mb":             bToMb(m.Sys),
 		"gc_runs":                   m.NumGC,
 		"goroutines":                runtime.NumGoroutine(),
+		"ai_calls":                  aiUsage.Calls,
+		"ai_cache_hits":             aiUsage.CacheHits,
+		"ai_budget_denied":          aiUsage.BudgetDenied,
+		"ai_rate_limited":           aiUsage.RateLimited,
 	}
 }
 
@@ -78,6 +88,81 @@ func StartStatusMonitoring() {
 	go LogProcessingStats()
 }
 
+// GetAIUsageStatus reports today's AI check usage of the groups managed by the admin
+func GetAIUsageStatus(adminID int64) string {
+	if storage.DB == nil {
+		return ""
+	}
+	groups, err := storage.NewGroupRepository(storage.DB).GetGroupsByAdminID(adminID)
+	if err != nil {
+		logger.Warningf("Error getting admin groups: %v", err)
+		return ""
+	}
+
+	budget := "unlimited"
+	if b := ai.GroupDailyBudget(); b > 0 {
+		budget = fmt.Sprintf("%d", b)
+	}
+	var sb strings.Builder
+	for _, group := range groups {
+		u := ai.Usage(group.GroupID)
+		name := group.GroupName
+		if name == "" {
+			name = fmt.Sprintf("%d", group.GroupID)
+		}
+		sb.WriteString(fmt.Sprintf("\n%s: %d/%s calls, %d cache hits, %d over budget, %d rate limited",
+			name, u.Calls, budget, u.CacheHits, u.BudgetDenied, u.RateLimited))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "\n=== AI Usage Of Your Groups ===" + sb.String()
+}
+
+// editSpamWindow is the period the flagged edits of /status are counted over
+const editSpamWindow = 7 * 24 * time.Hour
+
+// GetEditSpamStatus reports the messages flagged after an edit in the groups managed by the admin, by reason
+func GetEditSpamStatus(adminID int64) string {
+	if storage.DB == nil {
+		return ""
+	}
+	groups, err := storage.NewGroupRepository(storage.DB).GetGroupsByAdminID(adminID)
+	if err != nil {
+		logger.Warningf("Error getting admin groups: %v", err)
+		return ""
+	}
+
+	since := time.Now().Add(-editSpamWindow)
+	repository := storage.NewBanRepository(storage.DB)
+	var sb strings.Builder
+	for _, group := range groups {
+		counts, err := repository.CountEditedByReason(group.GroupID, since)
+		if err != nil {
+			logger.Warningf("Error counting edited spam of group %d: %v", group.GroupID, err)
+			continue
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		var total int64
+		parts := make([]string, len(counts))
+		for i, c := range counts {
+			total += c.Count
+			parts[i] = fmt.Sprintf("%s %d", strings.TrimPrefix(c.Reason, "reason_"), c.Count)
+		}
+		name := group.GroupName
+		if name == "" {
+			name = fmt.Sprintf("%d", group.GroupID)
+		}
+		sb.WriteString(fmt.Sprintf("\n%s: %d (%s)", name, total, strings.Join(parts, ", ")))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "\n=== Spam Edits Of Your Groups (7 days) ===" + sb.String()
+}
+
 // bToMb 将字节转换为MB
 func bToMb(b uint64) uint64 {
 	return b / 1024 / 1024
@@ -94,12 +179,14 @@ Chat Member Updates: %d
 Callback Queries: %d
 Errors: %d
 Timeouts: %d
+Spam Edits Flagged: %d
 Active Handlers: %d/%d
 Memory Usage: %d MB
 Total Allocated: %d MB
 System Memory: %d MB
 GC Runs: %d
 Goroutines: %d
+AI Checks Today: %d calls, %d cache hits, %d over budget, %d rate limited
 =====================================`,
 		stats["uptime_seconds"],
 		stats["total_messages"],
@@ -107,6 +194,7 @@ Goroutines: %d
 		stats["total_callback_queries"],
 		stats["total_errors"],
 		stats["total_timeouts"],
+		stats["total_edit_spam"],
 		stats["active_handlers"],
 		stats["max_concurrent_messages"],
 		stats["memory_usage_mb"],
@@ -114,5 +202,9 @@ Goroutines: %d
 		stats["sys_memory_mb"],
 		stats["gc_runs"],
 		stats["goroutines"],
+		stats["ai_calls"],
+		stats["ai_cache_hits"],
+		stats["ai_budget_denied"],
+		stats["ai_rate_limited"],
 	)
 }
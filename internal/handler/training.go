@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/rules"
+	"tg-antispam/internal/service"
+	"tg-antispam/internal/storage"
+)
+
+// trainingModes keeps the label forwarded messages are trained with, by admin user ID
+var (
+	trainingModes   = make(map[int64]bool)
+	trainingModesMu sync.Mutex
+)
+
+// learnsSpam reports whether a message removed for reason is fed to the Bayes classifier as spam:
+// content reasons except the classifier's own. Duplicate campaigns are learned, their copies are spam whatever they say.
+func learnsSpam(reason string) bool {
+	return rules.ContentReason(reason) && reason != rules.ReasonBayesSpam
+}
+
+// learnFromUnban teaches the classifier that the messages behind the user's active restrictions were ham
+func learnFromUnban(groupID, userID int64) {
+	records, err := service.GetUserActiveBanRecords(userID, groupID)
+	if err != nil {
+		logger.Warningf("Error getting ban records of user %d: %v", userID, err)
+		return
+	}
+	for _, record := range records {
+		if record.MessageText == "" {
+			continue
+		}
+		if learnsSpam(record.Reason) {
+			service.RetrainBayes(record.MessageText, false)
+		} else {
+			service.TrainBayes(record.MessageText, false)
+		}
+		service.SetBanRecordFeedback(record.ID, "ham")
+		logger.Infof("Learned message of unbanned user %d in group %d as ham", userID, groupID)
+	}
+}
+
+// learnFromBan records the admin's confirmation that the message behind the user's latest restriction was spam.
+// The classifier is retrained if an unban taught the message as ham, and trained if it was not learned
+// when the message was deleted, e.g. one the classifier flagged itself. Messages removed for non-content
+// reasons like flooding stay out of the classifier, what they say is not why they were removed.
+func learnFromBan(groupID, userID int64) {
+	record, err := service.GetLatestMessageBanRecord(groupID, userID)
+	if err != nil {
+		logger.Warningf("Error getting ban record of user %d: %v", userID, err)
+		return
+	}
+	if record == nil || record.Feedback == "spam" {
+		return
+	}
+	switch {
+	case record.MessageText == "":
+	case record.IsUnbanned:
+		// the unban taught the message as ham
+		service.RetrainBayes(record.MessageText, true)
+	case rules.ContentReason(record.Reason) && !learnsSpam(record.Reason):
+		service.TrainBayes(record.MessageText, true)
+	}
+	service.SetBanRecordFeedback(record.ID, "spam")
+	logger.Infof("Learned message of banned user %d in group %d as spam", userID, groupID)
+}
+
+// handleTrainCommand handles /train in private chat
+//
+//	/train spam|ham   label the replied forwarded message, or all following forwarded messages
+//	/train off        stop labeling forwarded messages
+//	/train            show usage and model statistics
+func handleTrainCommand(bot *telego.Bot, message telego.Message, args []string) error {
+	if message.Chat.Type != "private" {
+		return PrivateChatWarning(bot, message)
+	}
+
+	language := GetBotLang(bot, message)
+	if !isManagingAdmin(message.From.ID) {
+		return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(language, "empty_group_list"))
+	}
+
+	if len(args) != 1 || (args[0] != "spam" && args[0] != "ham" && args[0] != "off") {
+		spam, ham, vocabulary := service.BayesStats()
+		return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(language, "train_usage")+"\n\n"+
+			fmt.Sprintf(models.GetTranslation(language, "train_stats"), spam, ham, vocabulary))
+	}
+
+	trainingModesMu.Lock()
+	defer trainingModesMu.Unlock()
+	if args[0] == "off" {
+		delete(trainingModes, message.From.ID)
+		return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(language, "train_mode_off"))
+	}
+
+	spam := args[0] == "spam"
+	if message.ReplyToMessage != nil {
+		return trainMessage(bot, message.Chat.ID, message.ReplyToMessage, spam, language)
+	}
+	trainingModes[message.From.ID] = spam
+	return sendHTMLReply(bot, message.Chat.ID, models.GetTranslation(language, "train_mode_"+args[0]))
+}
+
+// handleTrainingMessage labels a message forwarded to the bot while the admin is in training mode.
+// It reports whether the message was consumed.
+func handleTrainingMessage(bot *telego.Bot, message telego.Message) bool {
+	if message.ForwardOrigin == nil {
+		return false
+	}
+
+	trainingModesMu.Lock()
+	spam, ok := trainingModes[message.From.ID]
+	trainingModesMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	if err := trainMessage(bot, message.Chat.ID, &message, spam, GetBotLang(bot, message)); err != nil {
+		logger.Warningf("Error replying to training message: %v", err)
+	}
+	return true
+}
+
+// trainMessage teaches the classifier the text of a message with the given label
+func trainMessage(bot *telego.Bot, chatID int64, message *telego.Message, spam bool, language string) error {
+	text := rules.MessageText(message)
+	if text == "" {
+		return sendHTMLReply(bot, chatID, models.GetTranslation(language, "train_no_text"))
+	}
+
+	service.TrainBayes(text, spam)
+	key := "train_learned_ham"
+	if spam {
+		key = "train_learned_spam"
+	}
+	spamCount, hamCount, _ := service.BayesStats()
+	return sendHTMLReply(bot, chatID, fmt.Sprintf(models.GetTranslation(language, key), spamCount, hamCount))
+}
+
+// isManagingAdmin reports whether the user manages at least one group with the bot
+func isManagingAdmin(userID int64) bool {
+	if storage.DB == nil {
+		return false
+	}
+	groups, err := storage.NewGroupRepository(storage.DB).GetGroupsByAdminID(userID)
+	if err != nil {
+		logger.Warningf("Error getting admin groups: %v", err)
+		return false
+	}
+	return len(groups) > 0
+}
@@ -0,0 +1,100 @@
+// Package imagehash computes perceptual hashes of images so that re-encoded
+// or resized copies of the same picture can be matched by Hamming distance.
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	sampleSize = 32 // the image is reduced to sampleSize x sampleSize before the DCT
+	hashSize   = 8  // the top-left hashSize x hashSize DCT coefficients form the hash
+)
+
+// Decode decodes a JPEG or PNG image and returns its perceptual hash
+func Decode(data []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	return PHash(img), nil
+}
+
+// PHash returns the 64-bit DCT based perceptual hash of img
+func PHash(img image.Image) uint64 {
+	pixels := grayscale(img)
+
+	// 2D DCT of the reduced image, only the low frequencies are needed
+	var coeffs [hashSize * hashSize]float64
+	for u := 0; u < hashSize; u++ {
+		for v := 0; v < hashSize; v++ {
+			sum := 0.0
+			for x := 0; x < sampleSize; x++ {
+				for y := 0; y < sampleSize; y++ {
+					sum += pixels[x][y] *
+						math.Cos(float64((2*x+1)*u)*math.Pi/(2*sampleSize)) *
+						math.Cos(float64((2*y+1)*v)*math.Pi/(2*sampleSize))
+				}
+			}
+			coeffs[u*hashSize+v] = sum
+		}
+	}
+
+	// compare against the median, ignoring the DC term which only reflects brightness
+	sorted := make([]float64, len(coeffs)-1)
+	copy(sorted, coeffs[1:])
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// Distance returns the number of differing bits between two hashes
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// grayscale reduces img to sampleSize x sampleSize luminance values by box averaging
+func grayscale(img image.Image) [sampleSize][sampleSize]float64 {
+	var pixels [sampleSize][sampleSize]float64
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return pixels
+	}
+
+	for x := 0; x < sampleSize; x++ {
+		x0, x1 := bounds.Min.X+x*width/sampleSize, bounds.Min.X+(x+1)*width/sampleSize
+		if x1 <= x0 {
+			x1 = x0 + 1
+		}
+		for y := 0; y < sampleSize; y++ {
+			y0, y1 := bounds.Min.Y+y*height/sampleSize, bounds.Min.Y+(y+1)*height/sampleSize
+			if y1 <= y0 {
+				y1 = y0 + 1
+			}
+			sum, count := 0.0, 0
+			for px := x0; px < x1; px++ {
+				for py := y0; py < y1; py++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					count++
+				}
+			}
+			pixels[x][y] = sum / float64(count) / 65535
+		}
+	}
+	return pixels
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// AvatarHash is the perceptual hash of a known spam profile photo.
+// Hashes with GroupID 0 are global and apply to every group.
+type AvatarHash struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	GroupID      int64  `gorm:"index;not null"`
+	Hash         uint64 `gorm:"not null"`
+	SourceUserID int64
+	AddedBy      string `gorm:"size:32"`
+	CreatedAt    time.Time
+}
@@ -3,15 +3,26 @@ package models
 import "time"
 
 // BanRecord stores information about user bans and unbans
-// It records the group, user, reason, and unban status
-// along with creation and update timestamps.
+// It records the group, user, reason, the risk score breakdown, the estimated account age, the text of the
+// offending message and unban status along with creation and update timestamps.
+// Records with the "delete" and "mute" actions only log a deleted message or a temporary mute and are never active bans.
+// Feedback is the admin's judgement of the message, "spam" or "ham", empty until an admin acts on the record.
+// Edited marks records of messages that were flagged after an edit, the reason is the one of the rule that flagged them;
+// /status counts them by reason.
 type BanRecord struct {
-	ID         uint   `gorm:"primaryKey;autoIncrement"`
-	GroupID    int64  `gorm:"index;not null"`
-	UserID     int64  `gorm:"index;not null"`
-	Reason     string `gorm:"type:text"`
-	IsUnbanned bool   `gorm:"default:false"`
-	UnbannedBy string `gorm:"default:''"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID               uint   `gorm:"primaryKey;autoIncrement"`
+	GroupID          int64  `gorm:"index;not null"`
+	UserID           int64  `gorm:"index;not null"`
+	Reason           string `gorm:"type:text"`
+	Action           string `gorm:"default:'restrict'"`
+	Score            int    `gorm:"default:0"`
+	ScoreDetail      string `gorm:"type:text"`
+	AccountCreatedAt *time.Time
+	MessageText      string `gorm:"type:text"`
+	Feedback         string `gorm:"default:''"`
+	Edited           bool   `gorm:"default:false"`
+	IsUnbanned       bool   `gorm:"default:false"`
+	UnbannedBy       string `gorm:"default:''"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
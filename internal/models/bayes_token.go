@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// BayesToken stores how many spam and ham messages a token of the spam classifier was seen in
+type BayesToken struct {
+	Token     string `gorm:"primaryKey;size:191"`
+	Spam      int    `gorm:"not null;default:0"`
+	Ham       int    `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
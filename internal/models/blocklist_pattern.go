@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fields a blocklist pattern can be matched against
+const (
+	BlocklistFieldFirstName   = "first_name"
+	BlocklistFieldLastName    = "last_name"
+	BlocklistFieldUsername    = "username"
+	BlocklistFieldBio         = "bio"
+	BlocklistFieldMessageText = "message_text"
+)
+
+// Match types of a blocklist pattern
+const (
+	BlocklistMatchSubstring = "substring"
+	BlocklistMatchRegex     = "regex"
+	BlocklistMatchGlob      = "glob"
+)
+
+// Actions taken when a blocklist pattern matches
+const (
+	BlocklistActionDelete   = "delete"
+	BlocklistActionRestrict = "restrict"
+	BlocklistActionBan      = "ban"
+)
+
+var (
+	BlocklistFields     = []string{BlocklistFieldFirstName, BlocklistFieldLastName, BlocklistFieldUsername, BlocklistFieldBio, BlocklistFieldMessageText}
+	BlocklistMatchTypes = []string{BlocklistMatchSubstring, BlocklistMatchRegex, BlocklistMatchGlob}
+	BlocklistActions    = []string{BlocklistActionDelete, BlocklistActionRestrict, BlocklistActionBan}
+)
+
+// BlocklistPattern is a per-group custom pattern for names, usernames, bios or message text
+type BlocklistPattern struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	GroupID   int64  `gorm:"index;not null"`
+	Field     string `gorm:"size:32;not null"`
+	MatchType string `gorm:"size:16;not null"`
+	Action    string `gorm:"size:16;not null"`
+	Pattern   string `gorm:"size:255;not null"`
+	CreatedBy int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	re *regexp.Regexp
+}
+
+// Compile validates the pattern and prepares it for matching.
+// All match types are case-insensitive.
+func (p *BlocklistPattern) Compile() error {
+	if !containsString(BlocklistFields, p.Field) {
+		return fmt.Errorf("unknown field %q", p.Field)
+	}
+	if !containsString(BlocklistActions, p.Action) {
+		return fmt.Errorf("unknown action %q", p.Action)
+	}
+	if p.Pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+
+	var expr string
+	switch p.MatchType {
+	case BlocklistMatchSubstring:
+		expr = regexp.QuoteMeta(p.Pattern)
+	case BlocklistMatchRegex:
+		expr = p.Pattern
+	case BlocklistMatchGlob:
+		expr = globToRegex(p.Pattern)
+	default:
+		return fmt.Errorf("unknown match type %q", p.MatchType)
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return err
+	}
+	p.re = re
+	return nil
+}
+
+// Match reports whether the value matches the pattern
+func (p *BlocklistPattern) Match(value string) bool {
+	if value == "" {
+		return false
+	}
+	if p.re == nil && p.Compile() != nil {
+		return false
+	}
+	return p.re.MatchString(value)
+}
+
+// globToRegex converts a glob (* and ?) into an anchored regular expression
+func globToRegex(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// BlocklistManager caches blocklist patterns per group
+type BlocklistManager struct {
+	patterns map[int64][]*BlocklistPattern
+	nextID   uint
+	mu       sync.RWMutex
+}
+
+func NewBlocklistManager() *BlocklistManager {
+	return &BlocklistManager{
+		patterns: make(map[int64][]*BlocklistPattern),
+	}
+}
+
+// Get returns the patterns of a group
+func (m *BlocklistManager) Get(groupID int64) []*BlocklistPattern {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	patterns := make([]*BlocklistPattern, len(m.patterns[groupID]))
+	copy(patterns, m.patterns[groupID])
+	return patterns
+}
+
+// Add caches a pattern, assigning an ID if it has none (database disabled)
+func (m *BlocklistManager) Add(pattern *BlocklistPattern) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if pattern.ID == 0 {
+		m.nextID++
+		pattern.ID = m.nextID
+	} else if pattern.ID > m.nextID {
+		m.nextID = pattern.ID
+	}
+	m.patterns[pattern.GroupID] = append(m.patterns[pattern.GroupID], pattern)
+}
+
+// Remove deletes a pattern of a group by ID, returning false if it was not found
+func (m *BlocklistManager) Remove(groupID int64, id uint) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, pattern := range m.patterns[groupID] {
+		if pattern.ID == id {
+			m.patterns[groupID] = append(m.patterns[groupID][:i], m.patterns[groupID][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
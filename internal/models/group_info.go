@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 	"tg-antispam/internal/logger"
@@ -9,29 +10,97 @@ import (
 
 // GroupInfo represents group information and settings
 type GroupInfo struct {
-	ID                 uint  `gorm:"primaryKey;autoIncrement"`
-	GroupID            int64 `gorm:"uniqueIndex;not null"`
-	GroupName          string
-	GroupLink          string
-	AdminID            int64
-	IsAdmin            bool
-	WaitSec            int    `gorm:"default:3"`
-	EnableNotification bool   `gorm:"default:true"`
-	BanPremium         bool   `gorm:"default:true"`
-	BanRandomUsername  bool   `gorm:"default:true"`
-	BanEmojiName       bool   `gorm:"default:true"`
-	BanBioLink         bool   `gorm:"default:true"`
-	EnableCAS          bool   `gorm:"default:true"`
-	EnableAicheck      bool   `gorm:"default:false"`
-	Language           string `gorm:"default:zh_CN"`
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	ID                      uint  `gorm:"primaryKey;autoIncrement"`
+	GroupID                 int64 `gorm:"uniqueIndex;not null"`
+	GroupName               string
+	GroupLink               string
+	AdminID                 int64
+	IsAdmin                 bool
+	WaitSec                 int    `gorm:"default:3"`
+	EnableNotification      bool   `gorm:"default:true"`
+	BanPremium              bool   `gorm:"default:true"`
+	BanRandomUsername       bool   `gorm:"default:true"`
+	BanEmojiName            bool   `gorm:"default:true"`
+	BanBioLink              bool   `gorm:"default:true"`
+	EnableCAS               bool   `gorm:"default:true"`
+	EnableAicheck           bool   `gorm:"default:false"`
+	EnableAiProfile         bool   `gorm:"default:false"`
+	RestrictScore           int    `gorm:"default:30"`
+	BanScore                int    `gorm:"default:100"`
+	EmojiMinCount           int    `gorm:"default:2"`
+	EmojiRatio              int    `gorm:"default:0"`
+	RandomUsernameThreshold int    `gorm:"default:70"`
+	MinAccountAgeDays       int    `gorm:"default:0"`
+	AccountAgeAction        string `gorm:"default:restrict"`
+	BanAvatarHash           bool   `gorm:"default:true"`
+	BanNoAvatar             bool   `gorm:"default:false"`
+	BanImpersonation        bool   `gorm:"default:true"`
+	BanInvisibleChars       bool   `gorm:"default:true"`
+	BlocklistProviders      string `gorm:"size:255"`
+	EnableMessageScan       bool   `gorm:"default:true"`
+	MessageProviderCheck    bool   `gorm:"default:true"`
+	MessageAction           string `gorm:"default:restrict"`
+	EnableBayes             bool   `gorm:"default:true"`
+	AiPolicy                string `gorm:"type:text"`
+	FloodMessages           int    `gorm:"default:0"`
+	FloodMedia              int    `gorm:"default:0"`
+	FloodMentions           int    `gorm:"default:0"`
+	FloodWindowSec          int    `gorm:"default:10"`
+	FloodAction             string `gorm:"default:mute"`
+	FloodMuteMinutes        int    `gorm:"default:10"`
+	DuplicateGroups         int    `gorm:"default:0"`
+	DuplicateUsers          int    `gorm:"default:0"`
+	EnableEditScan          bool   `gorm:"default:true"`
+	EditWindowMinutes       int    `gorm:"default:0"`
+	BlockInlineBots         bool   `gorm:"default:false"`
+	ForwardChannelPolicy    string `gorm:"default:allow"`
+	ForwardChannelAction    string `gorm:"default:delete"`
+	BlockHiddenForwards     bool   `gorm:"default:false"`
+	HiddenForwardAction     string `gorm:"default:delete"`
+	Language                string `gorm:"default:zh_CN"`
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
 }
 
 func (g *GroupInfo) GetLinkedGroupName() string {
 	return fmt.Sprintf("<a href=\"%s\">%s</a>", g.GroupLink, g.GroupName)
 }
 
+// CASProviderName is the blocklist provider switched by EnableCAS
+const CASProviderName = "cas"
+
+// UsesBlocklistProvider reports whether joining users are checked against the named provider.
+// CAS follows EnableCAS, other providers are kept comma separated in BlocklistProviders.
+func (g *GroupInfo) UsesBlocklistProvider(name string) bool {
+	if name == CASProviderName {
+		return g.EnableCAS
+	}
+	for _, selected := range strings.Split(g.BlocklistProviders, ",") {
+		if selected == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetBlocklistProvider selects or deselects a provider for the group
+func (g *GroupInfo) SetBlocklistProvider(name string, enabled bool) {
+	if name == CASProviderName {
+		g.EnableCAS = enabled
+		return
+	}
+	var selected []string
+	for _, existing := range strings.Split(g.BlocklistProviders, ",") {
+		if existing != "" && existing != name {
+			selected = append(selected, existing)
+		}
+	}
+	if enabled {
+		selected = append(selected, name)
+	}
+	g.BlocklistProviders = strings.Join(selected, ",")
+}
+
 // GroupInfoManager manages cached group info
 type GroupInfoManager struct {
 	GroupInfoMap   map[int64]*GroupInfo
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// Names of the per-group value lists
+const (
+	GroupListBioAllow       = "bio_allow"
+	GroupListBioDeny        = "bio_deny"
+	GroupListInlineBotAllow = "inline_bot_allow"
+	GroupListForwardAllow   = "forward_allow"
+	GroupListDomainAllow    = "domain_allow"
+	GroupListDomainDeny     = "domain_deny"
+)
+
+var GroupListNames = []string{GroupListBioAllow, GroupListBioDeny, GroupListInlineBotAllow, GroupListForwardAllow, GroupListDomainAllow, GroupListDomainDeny}
+
+// GroupListEntry is a value in one of a group's allow or deny lists
+type GroupListEntry struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	GroupID   int64  `gorm:"uniqueIndex:idx_group_list_value;not null"`
+	List      string `gorm:"uniqueIndex:idx_group_list_value;size:32;not null"`
+	Value     string `gorm:"uniqueIndex:idx_group_list_value;size:255;not null"`
+	CreatedBy int64
+	CreatedAt time.Time
+}
+
+// GroupListManager caches the list values of each group
+type GroupListManager struct {
+	lists map[int64]map[string][]string
+	mu    sync.RWMutex
+}
+
+func NewGroupListManager() *GroupListManager {
+	return &GroupListManager{
+		lists: make(map[int64]map[string][]string),
+	}
+}
+
+// Get returns the values of a group's list
+func (m *GroupListManager) Get(groupID int64, list string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	values := make([]string, len(m.lists[groupID][list]))
+	copy(values, m.lists[groupID][list])
+	return values
+}
+
+// Add caches a value, returning false if the list already contains it
+func (m *GroupListManager) Add(groupID int64, list, value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lists[groupID] == nil {
+		m.lists[groupID] = make(map[string][]string)
+	}
+	if containsString(m.lists[groupID][list], value) {
+		return false
+	}
+	m.lists[groupID][list] = append(m.lists[groupID][list], value)
+	return true
+}
+
+// Remove deletes a value from a group's list, returning false if it was not found
+func (m *GroupListManager) Remove(groupID int64, list, value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := m.lists[groupID][list]
+	for i, existing := range values {
+		if existing == value {
+			m.lists[groupID][list] = append(values[:i], values[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -108,6 +108,7 @@ var Translations = map[string]Translation{
 		"reason_cas_blacklisted": "用户在 CAS 黑名单中",
 		"reason_ai_spam":         "被 AI 判定为垃圾消息",
 		"reason_join_group":      "加入群组",
+		"reason_invisible_chars": "姓名含有隐形或双向控制字符",
 
 		// Toggle action response messages
 		"premium_ban_enabled":          "已启用默认封禁Premium用户",
@@ -138,6 +139,260 @@ var Translations = map[string]Translation{
 		"wait_sec_updated": "封禁等待时间已更新为 %d 秒",
 		"seconds":          "秒",
 		"unban_user":       "解除封禁",
+
+		// Risk score thresholds
+		"help_cmd_restrict_score": "/restrict_score - 设置限制用户的风险评分阈值",
+		"help_cmd_ban_score":      "/ban_score - 设置封禁用户的风险评分阈值",
+		"settings_score":          "- 风险评分阈值: 限制 ≥ %d, 封禁 ≥ %s",
+		"warning_banned":          "用户 %s 已被移出群组并封禁",
+		"warning_score":           "<b>风险评分</b>: %d (%s)",
+		"never":                   "从不",
+		"change_restrict_score":   "设置限制阈值",
+		"change_ban_score":        "设置封禁阈值",
+		"select_restrict_score":   "请选择限制用户的风险评分阈值:",
+		"select_ban_score":        "请选择封禁用户的风险评分阈值:",
+		"restrict_score_updated":  "限制阈值已更新为 %d",
+		"ban_score_updated":       "封禁阈值已更新为 %d（0 表示从不封禁）",
+
+		// Custom blocklist
+		"help_cmd_blocklist":  "/blocklist - 管理自定义黑名单规则（add|del|list）",
+		"reason_blocklist":    "命中自定义黑名单规则",
+		"blocklist_usage":     "用法：\n<code>/blocklist add &lt;字段&gt; &lt;匹配方式&gt; &lt;动作&gt; &lt;规则&gt;</code>\n<code>/blocklist del &lt;ID&gt;</code>\n<code>/blocklist list</code>\n\n字段：first_name, last_name, username, bio, message_text\n匹配方式：substring, regex, glob\n动作：delete, restrict, ban\n\n示例：<code>/blocklist add first_name substring restrict 客服</code>",
+		"blocklist_invalid":   "无效的黑名单规则：%s",
+		"blocklist_added":     "已添加黑名单规则 #%d",
+		"blocklist_deleted":   "已删除黑名单规则 #%d",
+		"blocklist_not_found": "未找到黑名单规则 #%d",
+		"blocklist_empty":     "该群组还没有自定义黑名单规则",
+		"blocklist_title":     "<b>自定义黑名单规则</b>",
+
+		// Emoji name policy
+		"help_cmd_emoji_min_count": "/emoji_min_count - 设置触发姓名表情检测的最少表情数",
+		"help_cmd_emoji_ratio":     "/emoji_ratio - 设置触发姓名表情检测的表情与文字比例",
+		"settings_emoji_policy":    "- 姓名表情策略: 至少 %d 个, 或比例 ≥ %s",
+		"change_emoji_min_count":   "设置最少表情数",
+		"change_emoji_ratio":       "设置表情比例",
+		"select_emoji_min_count":   "请选择触发姓名表情检测的最少表情数:",
+		"select_emoji_ratio":       "请选择触发姓名表情检测的表情与文字比例:",
+		"emoji_min_count_updated":  "最少表情数已更新为 %d",
+		"emoji_ratio_updated":      "表情比例已更新为 %d%%（0 表示禁用）",
+
+		// Random username threshold
+		"help_cmd_username_threshold": "/username_threshold - 设置随机用户名的判定概率阈值",
+		"settings_username_threshold": "- 随机用户名阈值: %d%%",
+		"change_username_threshold":   "设置随机用户名阈值",
+		"select_username_threshold":   "请选择判定为随机用户名的概率阈值:",
+		"username_threshold_updated":  "随机用户名阈值已更新为 %d%%",
+
+		// Account age
+		"help_cmd_min_account_age":      "/min_account_age - 设置最低账号年龄（按用户 ID 估算）",
+		"help_cmd_toggle_age_challenge": "/toggle_age_challenge - 切换年轻账号的处理方式：限制或验证",
+		"settings_account_age":          "- 最低账号年龄: %s（%s）",
+		"account_age_action_restrict":   "限制",
+		"account_age_action_challenge":  "限制并要求验证",
+		"change_min_account_age":        "设置最低账号年龄",
+		"toggle_age_challenge":          "切换年轻账号处理方式",
+		"select_min_account_age":        "请选择最低账号年龄，更年轻的账号将被限制或要求验证:",
+		"min_account_age_updated":       "最低账号年龄已更新为 %d 天（0 表示禁用）",
+		"age_challenge_enabled":         "年轻账号将被限制并要求完成验证",
+		"age_challenge_disabled":        "年轻账号将被直接限制",
+		"days":                          "天",
+		"reason_young_account":          "账号注册时间过短",
+		"warning_account_age":           "<b>账号创建时间（估计）</b>: %s（约 %d 天前）",
+
+		// Avatar checks
+		"help_cmd_toggle_avatar_hash": "/toggle_avatar_hash - 切换已知垃圾头像匹配",
+		"help_cmd_toggle_no_avatar":   "/toggle_no_avatar - 切换无头像用户检查",
+		"settings_avatar_hash":        "- 垃圾头像匹配: %s",
+		"settings_no_avatar":          "- 无头像用户检查: %s",
+		"toggle_avatar_hash":          "切换垃圾头像匹配",
+		"toggle_no_avatar":            "切换无头像检查",
+		"avatar_hash_ban_enabled":     "已启用垃圾头像匹配",
+		"avatar_hash_ban_disabled":    "已禁用垃圾头像匹配",
+		"no_avatar_ban_enabled":       "已启用无头像用户检查",
+		"no_avatar_ban_disabled":      "已禁用无头像用户检查",
+		"reason_spam_avatar":          "头像与已知垃圾账号头像相同",
+		"reason_no_avatar":            "没有设置头像",
+		"warning_block_avatar_button": "拉黑头像",
+		"avatar_blocked":              "已将该用户头像加入垃圾头像列表",
+		"avatar_not_found":            "该用户没有可见的头像",
+		"avatar_block_failed":         "获取用户头像失败，请稍后重试",
+
+		// Admin impersonation
+		"help_cmd_toggle_impersonation": "/toggle_impersonation - 切换冒充管理员检查",
+		"settings_impersonation":        "- 冒充管理员检查: %s",
+		"toggle_impersonation":          "切换冒充管理员检查",
+		"impersonation_ban_enabled":     "已启用冒充管理员检查",
+		"impersonation_ban_disabled":    "已禁用冒充管理员检查",
+		"reason_impersonation":          "名字或用户名冒充群管理员",
+		"warning_high_priority":         "🚨 <b>高优先级警告</b>",
+		"warning_evidence":              "详情: <code>%s</code>",
+
+		// Bio allow/deny lists
+		"help_cmd_bio_allow":           "/bio_allow - 管理个人简介白名单（add|del|list）",
+		"help_cmd_bio_deny":            "/bio_deny - 管理个人简介黑名单（add|del|list）",
+		"reason_bio_denied":            "个人简介包含群组黑名单中的内容",
+		"group_list_usage":             "用法：\n<code>/%[1]s add &lt;值&gt;</code>\n<code>/%[1]s del &lt;值&gt;</code>\n<code>/%[1]s list</code>",
+		"group_list_values_bio":        "值可以是 @用户名、t.me 链接、域名、电话号码或 BTC/ETH/TRON 钱包地址",
+		"group_list_values_inline_bot": "值是机器人的 @用户名，例如 @gif",
+		"group_list_invalid":           "无法识别的值：%s",
+		"group_list_added":             "已添加 <code>%s</code>",
+		"group_list_added_unchanged":   "<code>%s</code> 已在列表中",
+		"group_list_deleted":           "已删除 <code>%s</code>",
+		"group_list_deleted_unchanged": "列表中没有 <code>%s</code>",
+		"group_list_empty":             "该群组的 %s 列表为空",
+		"group_list_title":             "<b>%s 列表</b>",
+
+		// 黑名单数据源
+		"reason_provider_blacklisted":  "用户在外部黑名单中",
+		"settings_blocklist_providers": "- 黑名单数据源: %s",
+		"change_blocklist_providers":   "选择黑名单数据源",
+		"select_blocklist_providers":   "请选择检查新成员时使用的黑名单数据源:",
+		"provider_enabled":             "已启用数据源 %s",
+		"provider_disabled":            "已禁用数据源 %s",
+
+		// Message scan
+		"help_cmd_toggle_message_scan":     "/toggle_message_scan - 切换消息内容扫描",
+		"help_cmd_toggle_message_provider": "/toggle_message_provider - 切换可疑消息发送者的黑名单检查",
+		"help_cmd_toggle_ai_check":         "/toggle_ai_check - 切换可疑消息的 AI 检查",
+		"help_cmd_toggle_message_action":   "/toggle_message_action - 切换违规消息的处理方式：删除或删除并限制",
+		"settings_message_scan":            "- 消息内容扫描: %s",
+		"settings_message_provider":        "- 消息发送者黑名单检查: %s",
+		"settings_ai_check":                "- AI 消息检查: %s",
+		"settings_message_action":          "- 违规消息处理: %s",
+		"toggle_message_scan":              "切换消息扫描",
+		"toggle_message_provider":          "切换发送者黑名单检查",
+		"toggle_ai_check":                  "切换 AI 检查",
+		"toggle_message_action":            "切换违规消息处理",
+		"message_scan_enabled":             "已启用消息内容扫描",
+		"message_scan_disabled":            "已禁用消息内容扫描",
+		"message_provider_enabled":         "已启用消息发送者黑名单检查",
+		"message_provider_disabled":        "已禁用消息发送者黑名单检查",
+		"ai_check_enabled":                 "已启用 AI 消息检查",
+		"ai_check_disabled":                "已禁用 AI 消息检查",
+		"message_action_delete":            "仅删除消息",
+		"message_action_restrict":          "删除消息并限制发送者",
+		"message_action_delete_enabled":    "违规消息将被删除",
+		"message_action_restrict_enabled":  "违规消息将被删除，发送者将被限制",
+
+		// Bayes classifier
+		"help_cmd_toggle_bayes": "/toggle_bayes - 切换本地贝叶斯垃圾消息分类",
+		"help_cmd_train":        "/train spam|ham|off - 在私聊中将转发的消息标记为垃圾或正常消息",
+		"settings_bayes":        "- 贝叶斯分类: %s",
+		"toggle_bayes":          "切换贝叶斯分类",
+		"bayes_enabled":         "已启用贝叶斯分类",
+		"bayes_disabled":        "已禁用贝叶斯分类",
+		"reason_bayes_spam":     "被本地分类器判定为垃圾消息",
+		"train_usage":           "用法:\n回复一条转发的消息发送 <code>/train spam</code> 或 <code>/train ham</code> 为其打标签；\n直接发送 <code>/train spam</code> 或 <code>/train ham</code> 后，之后转发的消息都会按该标签学习，发送 <code>/train off</code> 结束。",
+		"train_stats":           "模型已学习 %d 条垃圾消息、%d 条正常消息，共 %d 个词。",
+		"train_mode_spam":       "之后转发给我的消息将被学习为垃圾消息，发送 /train off 结束。",
+		"train_mode_ham":        "之后转发给我的消息将被学习为正常消息，发送 /train off 结束。",
+		"train_mode_off":        "已结束训练模式。",
+		"train_no_text":         "这条消息没有可学习的文本。",
+		"train_learned_spam":    "已学习为垃圾消息（共 %d 条垃圾、%d 条正常消息）。",
+		"train_learned_ham":     "已学习为正常消息（共 %d 条垃圾、%d 条正常消息）。",
+
+		// AI policy
+		"help_cmd_ai_policy": "/ai_policy - 设置群组的 AI 审核策略，描述本群认定的垃圾消息",
+		"settings_ai_policy": "- AI 审核策略: %s",
+		"ai_policy_default":  "默认策略",
+		"ai_policy_custom":   "自定义策略",
+		"ai_policy_current":  "当前 AI 审核策略:\n<blockquote>%s</blockquote>",
+		"ai_policy_usage":    "用法：\n<code>/ai_policy &lt;策略描述&gt;</code> 设置策略\n<code>/ai_policy clear</code> 恢复默认策略\n\nAI 审核时还会参考本群最近确认的垃圾消息和被管理员解封的正常消息。",
+		"ai_policy_set":      "已设置 AI 审核策略:\n<blockquote>%s</blockquote>",
+		"ai_policy_cleared":  "已恢复默认 AI 审核策略",
+		"ai_policy_too_long": "AI 审核策略不能超过 %d 个字符",
+
+		// AI profile check
+		"help_cmd_toggle_ai_profile": "/toggle_ai_profile - 切换入群用户资料（名字、用户名、简介）的 AI 检查",
+		"settings_ai_profile":        "- AI 资料检查: %s",
+		"toggle_ai_profile":          "切换 AI 资料检查",
+		"ai_profile_enabled":         "已启用 AI 资料检查",
+		"ai_profile_disabled":        "已禁用 AI 资料检查",
+		"reason_ai_profile":          "资料被 AI 判定为垃圾账号",
+		"warning_ai_rationale":       "AI 判断: <code>%s</code>",
+
+		// Flood detection
+		"help_cmd_flood":        "/flood - 设置刷屏限制：每个时间窗口内的消息、媒体和提及数量",
+		"settings_flood":        "- 刷屏限制: %s",
+		"flood_limits":          "%s 条消息 / %s 个媒体 / %s 次提及，每 %d 秒，超出后%s",
+		"flood_action_delete":   "删除消息",
+		"flood_action_restrict": "限制用户",
+		"flood_action_mute":     "禁言 %d 分钟",
+		"flood_current":         "当前刷屏限制: %s",
+		"flood_usage":           "用法：\n<code>/flood &lt;消息数&gt; &lt;媒体数&gt; &lt;提及数&gt; [窗口秒数]</code> 设置限制，0 表示不限制\n<code>/flood action delete|restrict|mute [分钟]</code> 设置超出限制后的动作\n<code>/flood off</code> 关闭刷屏检测\n\n示例：<code>/flood 10 5 10 10</code>",
+		"reason_flood":          "刷屏",
+		"warning_muted":         "用户 %s 已被禁言 %d 分钟",
+
+		// Cross-group duplicate detection
+		"help_cmd_duplicates": "/duplicates - 设置跨群重复消息限制：同一消息最多可由多少个群组和用户发送",
+		"settings_duplicates": "- 跨群重复消息: %s",
+		"duplicates_limits":   "最多 %s 个群组 / %s 个用户",
+		"duplicates_current":  "当前跨群重复消息限制: %s",
+		"duplicates_usage":    "用法：\n<code>/duplicates &lt;群组数&gt; &lt;用户数&gt;</code> 同一文本或媒体在时间窗口内被超过该数量的不同群组或用户发送时视为垃圾消息，所有副本将被删除、发送者将被限制，0 表示不限制\n<code>/duplicates off</code> 关闭检测\n\n示例：<code>/duplicates 3 3</code>",
+		"reason_duplicate":    "在多个群组中重复发送相同消息",
+
+		// Edited messages
+		"help_cmd_toggle_edit_scan": "/toggle_edit_scan - 切换对编辑后消息的内容检查",
+		"help_cmd_edit_window":      "/edit_window - 设置消息编辑时限，超过时限的编辑将被删除",
+		"settings_edit_scan":        "- 编辑消息检查: %s，编辑时限: %s",
+		"toggle_edit_scan":          "切换编辑消息检查",
+		"change_edit_window":        "设置编辑时限",
+		"edit_scan_enabled":         "已启用编辑消息检查",
+		"edit_scan_disabled":        "已禁用编辑消息检查",
+		"select_edit_window":        "请选择编辑时限，发送后超过该时间再编辑的消息将被删除:",
+		"edit_window_updated":       "编辑时限已更新为 %d 分钟（0 表示不限制）",
+		"minutes":                   "分钟",
+		"warning_edited":            "<i>消息在编辑后被拦截</i>",
+		"reason_late_edit":          "超过编辑时限后编辑消息",
+
+		// Inline bots
+		"help_cmd_toggle_inline_bots": "/toggle_inline_bots - 切换删除通过非白名单内联机器人发送的消息",
+		"help_cmd_inline_bot_allow":   "/inline_bot_allow - 管理允许的内联机器人（add|del|list）",
+		"settings_inline_bots":        "- 拦截内联机器人消息: %s",
+		"toggle_inline_bots":          "切换内联机器人拦截",
+		"inline_bots_enabled":         "已启用内联机器人拦截，白名单以外的内联机器人消息将被删除",
+		"inline_bots_disabled":        "已禁用内联机器人拦截",
+		"reason_inline_bot":           "通过未允许的内联机器人发送消息",
+
+		// Forward policies
+		"help_cmd_forward_channels":       "/forward_channels - 切换频道转发策略：允许、全部拦截或仅允许白名单频道",
+		"help_cmd_forward_channel_action": "/forward_channel_action - 切换被拦截频道转发的处理方式：删除或删除并限制",
+		"help_cmd_forward_allow":          "/forward_allow - 管理允许转发的频道（add|del|list）",
+		"help_cmd_toggle_hidden_forwards": "/toggle_hidden_forwards - 切换拦截来自隐藏账号用户的转发",
+		"help_cmd_hidden_forward_action":  "/hidden_forward_action - 切换被拦截隐藏账号转发的处理方式：删除或删除并限制",
+		"settings_forward_channels":       "- 频道转发: %s，%s",
+		"settings_hidden_forwards":        "- 拦截隐藏账号转发: %s，%s",
+		"forward_channels_allow":          "允许",
+		"forward_channels_block":          "全部拦截",
+		"forward_channels_allowlist":      "仅允许白名单频道",
+		"change_forward_channels":         "切换频道转发策略",
+		"change_forward_channel_action":   "切换频道转发处理",
+		"toggle_hidden_forwards":          "切换隐藏账号转发拦截",
+		"change_hidden_forward_action":    "切换隐藏账号转发处理",
+		"forward_channels_changed":        "频道转发策略已设置为: %s",
+		"forward_action_changed":          "被拦截的转发将: %s",
+		"hidden_forwards_enabled":         "已启用隐藏账号转发拦截",
+		"hidden_forwards_disabled":        "已禁用隐藏账号转发拦截",
+		"reason_forward_channel":          "转发了不允许的频道消息",
+		"reason_forward_hidden":           "转发了隐藏账号用户的消息",
+		"group_list_values_forward":       "值是频道的数字 ID，例如 -1001234567890",
+
+		// Link domains
+		"help_cmd_domain_allow":    "/domain_allow - 管理允许的链接目标域名（add|del|list）",
+		"help_cmd_domain_deny":     "/domain_deny - 管理禁止的链接域名，包括短链接跳转经过的域名（add|del|list）",
+		"group_list_values_domain": "值是域名，例如 example.com，其子域名同样匹配",
+		"reason_url_domain":        "消息链接指向被禁止的域名",
+
+		// Invisible characters
+		"help_cmd_toggle_invisible_chars": "/toggle_invisible_chars - 切换名字隐藏字符检查",
+		"settings_invisible_chars":        "- 名字隐藏字符检查: %s",
+		"toggle_invisible_chars":          "切换隐藏字符检查",
+		"invisible_chars_enabled":         "已启用名字隐藏字符检查，该信号需配合其他信号才会达到限制分数",
+		"invisible_chars_disabled":        "已禁用名字隐藏字符检查",
+
+		// Score threshold checks
+		"restrict_score_not_below_ban": "限制阈值必须低于封禁阈值 (%d)",
+		"ban_score_not_above_restrict": "封禁阈值必须高于限制阈值 (%d)，或选择从不",
 	},
 
 	LangTraditionalChinese: {
@@ -244,6 +499,7 @@ var Translations = map[string]Translation{
 		"reason_cas_blacklisted": "用戶在 CAS 黑名單中",
 		"reason_ai_spam":         "被 AI 判定為垃圾訊息",
 		"reason_join_group":      "加入群組",
+		"reason_invisible_chars": "姓名含有隱形或雙向控制字元",
 		// Toggle action response messages
 		"premium_ban_enabled":          "已啟用默認封禁Premium用戶",
 		"premium_ban_disabled":         "已禁用默認封禁Premium用戶",
@@ -273,6 +529,260 @@ var Translations = map[string]Translation{
 		"wait_sec_updated": "封禁等待時間已更新為 %d 秒",
 		"seconds":          "秒",
 		"unban_user":       "解除封禁",
+
+		// Risk score thresholds
+		"help_cmd_restrict_score": "/restrict_score - 設置限制用戶的風險評分閾值",
+		"help_cmd_ban_score":      "/ban_score - 設置封禁用戶的風險評分閾值",
+		"settings_score":          "- 風險評分閾值: 限制 ≥ %d, 封禁 ≥ %s",
+		"warning_banned":          "用戶 %s 已被移出群組並封禁",
+		"warning_score":           "<b>風險評分</b>: %d (%s)",
+		"never":                   "從不",
+		"change_restrict_score":   "設置限制閾值",
+		"change_ban_score":        "設置封禁閾值",
+		"select_restrict_score":   "請選擇限制用戶的風險評分閾值:",
+		"select_ban_score":        "請選擇封禁用戶的風險評分閾值:",
+		"restrict_score_updated":  "限制閾值已更新為 %d",
+		"ban_score_updated":       "封禁閾值已更新為 %d（0 表示從不封禁）",
+
+		// Custom blocklist
+		"help_cmd_blocklist":  "/blocklist - 管理自訂黑名單規則（add|del|list）",
+		"reason_blocklist":    "命中自訂黑名單規則",
+		"blocklist_usage":     "用法：\n<code>/blocklist add &lt;欄位&gt; &lt;匹配方式&gt; &lt;動作&gt; &lt;規則&gt;</code>\n<code>/blocklist del &lt;ID&gt;</code>\n<code>/blocklist list</code>\n\n欄位：first_name, last_name, username, bio, message_text\n匹配方式：substring, regex, glob\n動作：delete, restrict, ban\n\n範例：<code>/blocklist add first_name substring restrict 客服</code>",
+		"blocklist_invalid":   "無效的黑名單規則：%s",
+		"blocklist_added":     "已新增黑名單規則 #%d",
+		"blocklist_deleted":   "已刪除黑名單規則 #%d",
+		"blocklist_not_found": "找不到黑名單規則 #%d",
+		"blocklist_empty":     "該群組還沒有自訂黑名單規則",
+		"blocklist_title":     "<b>自訂黑名單規則</b>",
+
+		// Emoji name policy
+		"help_cmd_emoji_min_count": "/emoji_min_count - 設置觸發姓名表情檢測的最少表情數",
+		"help_cmd_emoji_ratio":     "/emoji_ratio - 設置觸發姓名表情檢測的表情與文字比例",
+		"settings_emoji_policy":    "- 姓名表情策略: 至少 %d 個, 或比例 ≥ %s",
+		"change_emoji_min_count":   "設置最少表情數",
+		"change_emoji_ratio":       "設置表情比例",
+		"select_emoji_min_count":   "請選擇觸發姓名表情檢測的最少表情數:",
+		"select_emoji_ratio":       "請選擇觸發姓名表情檢測的表情與文字比例:",
+		"emoji_min_count_updated":  "最少表情數已更新為 %d",
+		"emoji_ratio_updated":      "表情比例已更新為 %d%%（0 表示停用）",
+
+		// Random username threshold
+		"help_cmd_username_threshold": "/username_threshold - 設置隨機用戶名的判定機率閾值",
+		"settings_username_threshold": "- 隨機用戶名閾值: %d%%",
+		"change_username_threshold":   "設置隨機用戶名閾值",
+		"select_username_threshold":   "請選擇判定為隨機用戶名的機率閾值:",
+		"username_threshold_updated":  "隨機用戶名閾值已更新為 %d%%",
+
+		// Account age
+		"help_cmd_min_account_age":      "/min_account_age - 設置最低帳號年齡（按用戶 ID 估算）",
+		"help_cmd_toggle_age_challenge": "/toggle_age_challenge - 切換年輕帳號的處理方式：限制或驗證",
+		"settings_account_age":          "- 最低帳號年齡: %s（%s）",
+		"account_age_action_restrict":   "限制",
+		"account_age_action_challenge":  "限制並要求驗證",
+		"change_min_account_age":        "設置最低帳號年齡",
+		"toggle_age_challenge":          "切換年輕帳號處理方式",
+		"select_min_account_age":        "請選擇最低帳號年齡，更年輕的帳號將被限制或要求驗證:",
+		"min_account_age_updated":       "最低帳號年齡已更新為 %d 天（0 表示停用）",
+		"age_challenge_enabled":         "年輕帳號將被限制並要求完成驗證",
+		"age_challenge_disabled":        "年輕帳號將被直接限制",
+		"days":                          "天",
+		"reason_young_account":          "帳號註冊時間過短",
+		"warning_account_age":           "<b>帳號建立時間（估計）</b>: %s（約 %d 天前）",
+
+		// Avatar checks
+		"help_cmd_toggle_avatar_hash": "/toggle_avatar_hash - 切換已知垃圾頭像比對",
+		"help_cmd_toggle_no_avatar":   "/toggle_no_avatar - 切換無頭像用戶檢查",
+		"settings_avatar_hash":        "- 垃圾頭像比對: %s",
+		"settings_no_avatar":          "- 無頭像用戶檢查: %s",
+		"toggle_avatar_hash":          "切換垃圾頭像比對",
+		"toggle_no_avatar":            "切換無頭像檢查",
+		"avatar_hash_ban_enabled":     "已啟用垃圾頭像比對",
+		"avatar_hash_ban_disabled":    "已禁用垃圾頭像比對",
+		"no_avatar_ban_enabled":       "已啟用無頭像用戶檢查",
+		"no_avatar_ban_disabled":      "已禁用無頭像用戶檢查",
+		"reason_spam_avatar":          "頭像與已知垃圾帳號頭像相同",
+		"reason_no_avatar":            "沒有設定頭像",
+		"warning_block_avatar_button": "封鎖頭像",
+		"avatar_blocked":              "已將該用戶頭像加入垃圾頭像列表",
+		"avatar_not_found":            "該用戶沒有可見的頭像",
+		"avatar_block_failed":         "取得用戶頭像失敗，請稍後重試",
+
+		// Admin impersonation
+		"help_cmd_toggle_impersonation": "/toggle_impersonation - 切換冒充管理員檢查",
+		"settings_impersonation":        "- 冒充管理員檢查: %s",
+		"toggle_impersonation":          "切換冒充管理員檢查",
+		"impersonation_ban_enabled":     "已啟用冒充管理員檢查",
+		"impersonation_ban_disabled":    "已禁用冒充管理員檢查",
+		"reason_impersonation":          "名字或用戶名冒充群組管理員",
+		"warning_high_priority":         "🚨 <b>高優先級警告</b>",
+		"warning_evidence":              "詳情: <code>%s</code>",
+
+		// Bio allow/deny lists
+		"help_cmd_bio_allow":           "/bio_allow - 管理個人簡介白名單（add|del|list）",
+		"help_cmd_bio_deny":            "/bio_deny - 管理個人簡介黑名單（add|del|list）",
+		"reason_bio_denied":            "個人簡介包含群組黑名單中的內容",
+		"group_list_usage":             "用法：\n<code>/%[1]s add &lt;值&gt;</code>\n<code>/%[1]s del &lt;值&gt;</code>\n<code>/%[1]s list</code>",
+		"group_list_values_bio":        "值可以是 @用戶名、t.me 連結、網域、電話號碼或 BTC/ETH/TRON 錢包地址",
+		"group_list_values_inline_bot": "值是機器人的 @用戶名，例如 @gif",
+		"group_list_invalid":           "無法識別的值：%s",
+		"group_list_added":             "已新增 <code>%s</code>",
+		"group_list_added_unchanged":   "<code>%s</code> 已在列表中",
+		"group_list_deleted":           "已刪除 <code>%s</code>",
+		"group_list_deleted_unchanged": "列表中沒有 <code>%s</code>",
+		"group_list_empty":             "該群組的 %s 列表為空",
+		"group_list_title":             "<b>%s 列表</b>",
+
+		// 黑名单数据源
+		"reason_provider_blacklisted":  "用戶在外部黑名單中",
+		"settings_blocklist_providers": "- 黑名單數據源: %s",
+		"change_blocklist_providers":   "選擇黑名單數據源",
+		"select_blocklist_providers":   "請選擇檢查新成員時使用的黑名單數據源:",
+		"provider_enabled":             "已啟用數據源 %s",
+		"provider_disabled":            "已停用數據源 %s",
+
+		// Message scan
+		"help_cmd_toggle_message_scan":     "/toggle_message_scan - 切換訊息內容掃描",
+		"help_cmd_toggle_message_provider": "/toggle_message_provider - 切換可疑訊息發送者的黑名單檢查",
+		"help_cmd_toggle_ai_check":         "/toggle_ai_check - 切換可疑訊息的 AI 檢查",
+		"help_cmd_toggle_message_action":   "/toggle_message_action - 切換違規訊息的處理方式：刪除或刪除並限制",
+		"settings_message_scan":            "- 訊息內容掃描: %s",
+		"settings_message_provider":        "- 訊息發送者黑名單檢查: %s",
+		"settings_ai_check":                "- AI 訊息檢查: %s",
+		"settings_message_action":          "- 違規訊息處理: %s",
+		"toggle_message_scan":              "切換訊息掃描",
+		"toggle_message_provider":          "切換發送者黑名單檢查",
+		"toggle_ai_check":                  "切換 AI 檢查",
+		"toggle_message_action":            "切換違規訊息處理",
+		"message_scan_enabled":             "已啟用訊息內容掃描",
+		"message_scan_disabled":            "已禁用訊息內容掃描",
+		"message_provider_enabled":         "已啟用訊息發送者黑名單檢查",
+		"message_provider_disabled":        "已禁用訊息發送者黑名單檢查",
+		"ai_check_enabled":                 "已啟用 AI 訊息檢查",
+		"ai_check_disabled":                "已禁用 AI 訊息檢查",
+		"message_action_delete":            "僅刪除訊息",
+		"message_action_restrict":          "刪除訊息並限制發送者",
+		"message_action_delete_enabled":    "違規訊息將被刪除",
+		"message_action_restrict_enabled":  "違規訊息將被刪除，發送者將被限制",
+
+		// Bayes classifier
+		"help_cmd_toggle_bayes": "/toggle_bayes - 切換本地貝葉斯垃圾訊息分類",
+		"help_cmd_train":        "/train spam|ham|off - 在私聊中將轉發的訊息標記為垃圾或正常訊息",
+		"settings_bayes":        "- 貝葉斯分類: %s",
+		"toggle_bayes":          "切換貝葉斯分類",
+		"bayes_enabled":         "已啟用貝葉斯分類",
+		"bayes_disabled":        "已禁用貝葉斯分類",
+		"reason_bayes_spam":     "被本地分類器判定為垃圾訊息",
+		"train_usage":           "用法:\n回覆一條轉發的訊息發送 <code>/train spam</code> 或 <code>/train ham</code> 為其打標籤；\n直接發送 <code>/train spam</code> 或 <code>/train ham</code> 後，之後轉發的訊息都會按該標籤學習，發送 <code>/train off</code> 結束。",
+		"train_stats":           "模型已學習 %d 條垃圾訊息、%d 條正常訊息，共 %d 個詞。",
+		"train_mode_spam":       "之後轉發給我的訊息將被學習為垃圾訊息，發送 /train off 結束。",
+		"train_mode_ham":        "之後轉發給我的訊息將被學習為正常訊息，發送 /train off 結束。",
+		"train_mode_off":        "已結束訓練模式。",
+		"train_no_text":         "這條訊息沒有可學習的文字。",
+		"train_learned_spam":    "已學習為垃圾訊息（共 %d 條垃圾、%d 條正常訊息）。",
+		"train_learned_ham":     "已學習為正常訊息（共 %d 條垃圾、%d 條正常訊息）。",
+
+		// AI policy
+		"help_cmd_ai_policy": "/ai_policy - 設定群組的 AI 審核策略，描述本群認定的垃圾訊息",
+		"settings_ai_policy": "- AI 審核策略: %s",
+		"ai_policy_default":  "預設策略",
+		"ai_policy_custom":   "自訂策略",
+		"ai_policy_current":  "目前 AI 審核策略:\n<blockquote>%s</blockquote>",
+		"ai_policy_usage":    "用法：\n<code>/ai_policy &lt;策略描述&gt;</code> 設定策略\n<code>/ai_policy clear</code> 恢復預設策略\n\nAI 審核時還會參考本群最近確認的垃圾訊息和被管理員解封的正常訊息。",
+		"ai_policy_set":      "已設定 AI 審核策略:\n<blockquote>%s</blockquote>",
+		"ai_policy_cleared":  "已恢復預設 AI 審核策略",
+		"ai_policy_too_long": "AI 審核策略不能超過 %d 個字元",
+
+		// AI profile check
+		"help_cmd_toggle_ai_profile": "/toggle_ai_profile - 切換入群用戶資料（名字、用戶名、簡介）的 AI 檢查",
+		"settings_ai_profile":        "- AI 資料檢查: %s",
+		"toggle_ai_profile":          "切換 AI 資料檢查",
+		"ai_profile_enabled":         "已啟用 AI 資料檢查",
+		"ai_profile_disabled":        "已禁用 AI 資料檢查",
+		"reason_ai_profile":          "資料被 AI 判定為垃圾帳號",
+		"warning_ai_rationale":       "AI 判斷: <code>%s</code>",
+
+		// Flood detection
+		"help_cmd_flood":        "/flood - 設定洗版限制：每個時間窗口內的訊息、媒體和提及數量",
+		"settings_flood":        "- 洗版限制: %s",
+		"flood_limits":          "%s 則訊息 / %s 個媒體 / %s 次提及，每 %d 秒，超出後%s",
+		"flood_action_delete":   "刪除訊息",
+		"flood_action_restrict": "限制用戶",
+		"flood_action_mute":     "禁言 %d 分鐘",
+		"flood_current":         "目前洗版限制: %s",
+		"flood_usage":           "用法：\n<code>/flood &lt;訊息數&gt; &lt;媒體數&gt; &lt;提及數&gt; [窗口秒數]</code> 設定限制，0 表示不限制\n<code>/flood action delete|restrict|mute [分鐘]</code> 設定超出限制後的動作\n<code>/flood off</code> 關閉洗版偵測\n\n範例：<code>/flood 10 5 10 10</code>",
+		"reason_flood":          "洗版",
+		"warning_muted":         "用戶 %s 已被禁言 %d 分鐘",
+
+		// Cross-group duplicate detection
+		"help_cmd_duplicates": "/duplicates - 設定跨群重複訊息限制：同一訊息最多可由多少個群組和用戶發送",
+		"settings_duplicates": "- 跨群重複訊息: %s",
+		"duplicates_limits":   "最多 %s 個群組 / %s 個用戶",
+		"duplicates_current":  "目前跨群重複訊息限制: %s",
+		"duplicates_usage":    "用法：\n<code>/duplicates &lt;群組數&gt; &lt;用戶數&gt;</code> 同一文字或媒體在時間窗口內被超過該數量的不同群組或用戶發送時視為垃圾訊息，所有副本將被刪除、發送者將被限制，0 表示不限制\n<code>/duplicates off</code> 關閉偵測\n\n範例：<code>/duplicates 3 3</code>",
+		"reason_duplicate":    "在多個群組中重複發送相同訊息",
+
+		// Edited messages
+		"help_cmd_toggle_edit_scan": "/toggle_edit_scan - 切換對編輯後訊息的內容檢查",
+		"help_cmd_edit_window":      "/edit_window - 設定訊息編輯時限，超過時限的編輯將被刪除",
+		"settings_edit_scan":        "- 編輯訊息檢查: %s，編輯時限: %s",
+		"toggle_edit_scan":          "切換編輯訊息檢查",
+		"change_edit_window":        "設定編輯時限",
+		"edit_scan_enabled":         "已啟用編輯訊息檢查",
+		"edit_scan_disabled":        "已禁用編輯訊息檢查",
+		"select_edit_window":        "請選擇編輯時限，發送後超過該時間再編輯的訊息將被刪除:",
+		"edit_window_updated":       "編輯時限已更新為 %d 分鐘（0 表示不限制）",
+		"minutes":                   "分鐘",
+		"warning_edited":            "<i>訊息在編輯後被攔截</i>",
+		"reason_late_edit":          "超過編輯時限後編輯訊息",
+
+		// Inline bots
+		"help_cmd_toggle_inline_bots": "/toggle_inline_bots - 切換刪除透過非白名單內聯機器人發送的訊息",
+		"help_cmd_inline_bot_allow":   "/inline_bot_allow - 管理允許的內聯機器人（add|del|list）",
+		"settings_inline_bots":        "- 攔截內聯機器人訊息: %s",
+		"toggle_inline_bots":          "切換內聯機器人攔截",
+		"inline_bots_enabled":         "已啟用內聯機器人攔截，白名單以外的內聯機器人訊息將被刪除",
+		"inline_bots_disabled":        "已禁用內聯機器人攔截",
+		"reason_inline_bot":           "透過未允許的內聯機器人發送訊息",
+
+		// Forward policies
+		"help_cmd_forward_channels":       "/forward_channels - 切換頻道轉發策略：允許、全部攔截或僅允許白名單頻道",
+		"help_cmd_forward_channel_action": "/forward_channel_action - 切換被攔截頻道轉發的處理方式：刪除或刪除並限制",
+		"help_cmd_forward_allow":          "/forward_allow - 管理允許轉發的頻道（add|del|list）",
+		"help_cmd_toggle_hidden_forwards": "/toggle_hidden_forwards - 切換攔截來自隱藏帳號用戶的轉發",
+		"help_cmd_hidden_forward_action":  "/hidden_forward_action - 切換被攔截隱藏帳號轉發的處理方式：刪除或刪除並限制",
+		"settings_forward_channels":       "- 頻道轉發: %s，%s",
+		"settings_hidden_forwards":        "- 攔截隱藏帳號轉發: %s，%s",
+		"forward_channels_allow":          "允許",
+		"forward_channels_block":          "全部攔截",
+		"forward_channels_allowlist":      "僅允許白名單頻道",
+		"change_forward_channels":         "切換頻道轉發策略",
+		"change_forward_channel_action":   "切換頻道轉發處理",
+		"toggle_hidden_forwards":          "切換隱藏帳號轉發攔截",
+		"change_hidden_forward_action":    "切換隱藏帳號轉發處理",
+		"forward_channels_changed":        "頻道轉發策略已設定為: %s",
+		"forward_action_changed":          "被攔截的轉發將: %s",
+		"hidden_forwards_enabled":         "已啟用隱藏帳號轉發攔截",
+		"hidden_forwards_disabled":        "已禁用隱藏帳號轉發攔截",
+		"reason_forward_channel":          "轉發了不允許的頻道訊息",
+		"reason_forward_hidden":           "轉發了隱藏帳號用戶的訊息",
+		"group_list_values_forward":       "值是頻道的數字 ID，例如 -1001234567890",
+
+		// Link domains
+		"help_cmd_domain_allow":    "/domain_allow - 管理允許的連結目標網域（add|del|list）",
+		"help_cmd_domain_deny":     "/domain_deny - 管理禁止的連結網域，包括短網址跳轉經過的網域（add|del|list）",
+		"group_list_values_domain": "值是網域，例如 example.com，其子網域同樣匹配",
+		"reason_url_domain":        "訊息連結指向被禁止的網域",
+
+		// Invisible characters
+		"help_cmd_toggle_invisible_chars": "/toggle_invisible_chars - 切換名字隱藏字元檢查",
+		"settings_invisible_chars":        "- 名字隱藏字元檢查: %s",
+		"toggle_invisible_chars":          "切換隱藏字元檢查",
+		"invisible_chars_enabled":         "已啟用名字隱藏字元檢查，該信號需配合其他信號才會達到限制分數",
+		"invisible_chars_disabled":        "已禁用名字隱藏字元檢查",
+
+		// Score threshold checks
+		"restrict_score_not_below_ban": "限制閾值必須低於封禁閾值 (%d)",
+		"ban_score_not_above_restrict": "封禁閾值必須高於限制閾值 (%d)，或選擇從不",
 	},
 
 	LangEnglish: {
@@ -387,6 +897,7 @@ var Translations = map[string]Translation{
 		"reason_cas_blacklisted": "User is on the CAS blacklist",
 		"reason_ai_spam":         "Classified as spam by AI",
 		"reason_join_group":      "Join group",
+		"reason_invisible_chars": "Name contains invisible or bidi control characters",
 
 		// Toggle action response messages
 		"premium_ban_enabled":          "Premium user ban enabled",
@@ -417,6 +928,260 @@ var Translations = map[string]Translation{
 		"wait_sec_updated": "Ban user wait time updated to %d seconds",
 		"seconds":          "seconds",
 		"unban_user":       "Unban user",
+
+		// Risk score thresholds
+		"help_cmd_restrict_score": "/restrict_score - Set the risk score threshold for restricting users",
+		"help_cmd_ban_score":      "/ban_score - Set the risk score threshold for banning users",
+		"settings_score":          "- Risk Score Thresholds: restrict ≥ %d, ban ≥ %s",
+		"warning_banned":          "User %s has been removed and banned from the group",
+		"warning_score":           "<b>Risk score</b>: %d (%s)",
+		"never":                   "Never",
+		"change_restrict_score":   "Set restrict threshold",
+		"change_ban_score":        "Set ban threshold",
+		"select_restrict_score":   "Please select the risk score threshold for restricting users:",
+		"select_ban_score":        "Please select the risk score threshold for banning users:",
+		"restrict_score_updated":  "Restrict threshold updated to %d",
+		"ban_score_updated":       "Ban threshold updated to %d (0 means never ban)",
+
+		// Custom blocklist
+		"help_cmd_blocklist":  "/blocklist - Manage custom blocklist patterns (add|del|list)",
+		"reason_blocklist":    "Matched a custom blocklist pattern",
+		"blocklist_usage":     "Usage:\n<code>/blocklist add &lt;field&gt; &lt;match_type&gt; &lt;action&gt; &lt;pattern&gt;</code>\n<code>/blocklist del &lt;ID&gt;</code>\n<code>/blocklist list</code>\n\nFields: first_name, last_name, username, bio, message_text\nMatch types: substring, regex, glob\nActions: delete, restrict, ban\n\nExample: <code>/blocklist add first_name substring restrict USDT</code>",
+		"blocklist_invalid":   "Invalid blocklist pattern: %s",
+		"blocklist_added":     "Blocklist pattern #%d added",
+		"blocklist_deleted":   "Blocklist pattern #%d deleted",
+		"blocklist_not_found": "Blocklist pattern #%d not found",
+		"blocklist_empty":     "This group has no custom blocklist patterns yet",
+		"blocklist_title":     "<b>Custom blocklist patterns</b>",
+
+		// Emoji name policy
+		"help_cmd_emoji_min_count": "/emoji_min_count - Set the minimum number of emoji that triggers the name emoji check",
+		"help_cmd_emoji_ratio":     "/emoji_ratio - Set the emoji-to-letter ratio that triggers the name emoji check",
+		"settings_emoji_policy":    "- Name Emoji Policy: at least %d, or ratio ≥ %s",
+		"change_emoji_min_count":   "Set Minimum Emoji",
+		"change_emoji_ratio":       "Set Emoji Ratio",
+		"select_emoji_min_count":   "Please select the minimum number of emoji that triggers the name emoji check:",
+		"select_emoji_ratio":       "Please select the emoji-to-letter ratio that triggers the name emoji check:",
+		"emoji_min_count_updated":  "Minimum emoji count updated to %d",
+		"emoji_ratio_updated":      "Emoji ratio updated to %d%% (0 means disabled)",
+
+		// Random username threshold
+		"help_cmd_username_threshold": "/username_threshold - Set the probability threshold for random usernames",
+		"settings_username_threshold": "- Random Username Threshold: %d%%",
+		"change_username_threshold":   "Set Username Threshold",
+		"select_username_threshold":   "Please select the probability from which a username counts as random:",
+		"username_threshold_updated":  "Random username threshold updated to %d%%",
+
+		// Account age
+		"help_cmd_min_account_age":      "/min_account_age - Set the minimum account age (estimated from the user ID)",
+		"help_cmd_toggle_age_challenge": "/toggle_age_challenge - Switch young accounts between restrict and challenge",
+		"settings_account_age":          "- Minimum Account Age: %s (%s)",
+		"account_age_action_restrict":   "restrict",
+		"account_age_action_challenge":  "restrict and challenge",
+		"change_min_account_age":        "Set Minimum Account Age",
+		"toggle_age_challenge":          "Toggle Young Account Action",
+		"select_min_account_age":        "Please select the minimum account age, younger accounts will be restricted or challenged:",
+		"min_account_age_updated":       "Minimum account age updated to %d days (0 means disabled)",
+		"age_challenge_enabled":         "Young accounts will be restricted and challenged",
+		"age_challenge_disabled":        "Young accounts will be restricted",
+		"days":                          "days",
+		"reason_young_account":          "Account was created recently",
+		"warning_account_age":           "<b>Account created (est.)</b>: %s (~%d days ago)",
+
+		// Avatar checks
+		"help_cmd_toggle_avatar_hash": "/toggle_avatar_hash - Toggle matching of known spam avatars",
+		"help_cmd_toggle_no_avatar":   "/toggle_no_avatar - Toggle the check for users without a profile photo",
+		"settings_avatar_hash":        "- Spam Avatar Check: %s",
+		"settings_no_avatar":          "- No Avatar Check: %s",
+		"toggle_avatar_hash":          "Toggle Spam Avatar Check",
+		"toggle_no_avatar":            "Toggle No Avatar Check",
+		"avatar_hash_ban_enabled":     "Spam avatar matching enabled",
+		"avatar_hash_ban_disabled":    "Spam avatar matching disabled",
+		"no_avatar_ban_enabled":       "No avatar check enabled",
+		"no_avatar_ban_disabled":      "No avatar check disabled",
+		"reason_spam_avatar":          "Profile photo matches a known spam avatar",
+		"reason_no_avatar":            "No profile photo",
+		"warning_block_avatar_button": "Block Avatar",
+		"avatar_blocked":              "The user's avatar was added to the spam avatar list",
+		"avatar_not_found":            "The user has no visible profile photo",
+		"avatar_block_failed":         "Failed to get the user's profile photo, please try again later",
+
+		// Admin impersonation
+		"help_cmd_toggle_impersonation": "/toggle_impersonation - Toggle the admin impersonation check",
+		"settings_impersonation":        "- Admin Impersonation Check: %s",
+		"toggle_impersonation":          "Toggle Impersonation Check",
+		"impersonation_ban_enabled":     "Admin impersonation check enabled",
+		"impersonation_ban_disabled":    "Admin impersonation check disabled",
+		"reason_impersonation":          "Name or username imitates a group admin",
+		"warning_high_priority":         "🚨 <b>High priority alert</b>",
+		"warning_evidence":              "Details: <code>%s</code>",
+
+		// Bio allow/deny lists
+		"help_cmd_bio_allow":           "/bio_allow - Manage the bio allow list (add|del|list)",
+		"help_cmd_bio_deny":            "/bio_deny - Manage the bio deny list (add|del|list)",
+		"reason_bio_denied":            "Bio contains an entry of the group's deny list",
+		"group_list_usage":             "Usage:\n<code>/%[1]s add &lt;value&gt;</code>\n<code>/%[1]s del &lt;value&gt;</code>\n<code>/%[1]s list</code>",
+		"group_list_values_bio":        "A value is a @username, t.me link, domain, phone number or BTC/ETH/TRON wallet address",
+		"group_list_values_inline_bot": "A value is the @username of a bot, e.g. @gif",
+		"group_list_invalid":           "Unrecognized value: %s",
+		"group_list_added":             "Added <code>%s</code>",
+		"group_list_added_unchanged":   "<code>%s</code> is already in the list",
+		"group_list_deleted":           "Deleted <code>%s</code>",
+		"group_list_deleted_unchanged": "<code>%s</code> is not in the list",
+		"group_list_empty":             "The %s list of this group is empty",
+		"group_list_title":             "<b>%s list</b>",
+
+		// 黑名单数据源
+		"reason_provider_blacklisted":  "User is on an external blocklist",
+		"settings_blocklist_providers": "- Blocklist Providers: %s",
+		"change_blocklist_providers":   "Choose Blocklist Providers",
+		"select_blocklist_providers":   "Please select the blocklists new members are checked against:",
+		"provider_enabled":             "Provider %s enabled",
+		"provider_disabled":            "Provider %s disabled",
+
+		// Message scan
+		"help_cmd_toggle_message_scan":     "/toggle_message_scan - Toggle message content scanning",
+		"help_cmd_toggle_message_provider": "/toggle_message_provider - Toggle the blocklist check of suspicious message senders",
+		"help_cmd_toggle_ai_check":         "/toggle_ai_check - Toggle the AI check of suspicious messages",
+		"help_cmd_toggle_message_action":   "/toggle_message_action - Switch flagged messages between delete and delete and restrict",
+		"settings_message_scan":            "- Message Scan: %s",
+		"settings_message_provider":        "- Message Sender Blocklist Check: %s",
+		"settings_ai_check":                "- AI Message Check: %s",
+		"settings_message_action":          "- Flagged Message Action: %s",
+		"toggle_message_scan":              "Toggle Message Scan",
+		"toggle_message_provider":          "Toggle Sender Blocklist Check",
+		"toggle_ai_check":                  "Toggle AI Check",
+		"toggle_message_action":            "Toggle Flagged Message Action",
+		"message_scan_enabled":             "Message scan enabled",
+		"message_scan_disabled":            "Message scan disabled",
+		"message_provider_enabled":         "Message sender blocklist check enabled",
+		"message_provider_disabled":        "Message sender blocklist check disabled",
+		"ai_check_enabled":                 "AI message check enabled",
+		"ai_check_disabled":                "AI message check disabled",
+		"message_action_delete":            "delete the message",
+		"message_action_restrict":          "delete the message and restrict the sender",
+		"message_action_delete_enabled":    "Flagged messages will be deleted",
+		"message_action_restrict_enabled":  "Flagged messages will be deleted and their senders restricted",
+
+		// Bayes classifier
+		"help_cmd_toggle_bayes": "/toggle_bayes - Toggle the local naive Bayes spam classifier",
+		"help_cmd_train":        "/train spam|ham|off - Label forwarded messages as spam or ham in private chat",
+		"settings_bayes":        "- Bayes Classifier: %s",
+		"toggle_bayes":          "Toggle Bayes Classifier",
+		"bayes_enabled":         "Bayes classifier enabled",
+		"bayes_disabled":        "Bayes classifier disabled",
+		"reason_bayes_spam":     "Classified as spam by the local classifier",
+		"train_usage":           "Usage:\nReply to a forwarded message with <code>/train spam</code> or <code>/train ham</code> to label it;\nsend <code>/train spam</code> or <code>/train ham</code> on its own to label every message you forward next, and <code>/train off</code> to stop.",
+		"train_stats":           "The model has learned %d spam and %d ham messages with %d tokens.",
+		"train_mode_spam":       "Messages you forward to me will be learned as spam, send /train off to stop.",
+		"train_mode_ham":        "Messages you forward to me will be learned as ham, send /train off to stop.",
+		"train_mode_off":        "Training mode ended.",
+		"train_no_text":         "This message has no text to learn.",
+		"train_learned_spam":    "Learned as spam (%d spam, %d ham messages in total).",
+		"train_learned_ham":     "Learned as ham (%d spam, %d ham messages in total).",
+
+		// AI policy
+		"help_cmd_ai_policy": "/ai_policy - Describe what this group counts as spam for the AI check",
+		"settings_ai_policy": "- AI Policy: %s",
+		"ai_policy_default":  "default policy",
+		"ai_policy_custom":   "custom policy",
+		"ai_policy_current":  "Current AI policy:\n<blockquote>%s</blockquote>",
+		"ai_policy_usage":    "Usage:\n<code>/ai_policy &lt;description&gt;</code> set the policy\n<code>/ai_policy clear</code> restore the default policy\n\nThe AI check also sees this group's recently confirmed spam and the messages admins unbanned.",
+		"ai_policy_set":      "AI policy set:\n<blockquote>%s</blockquote>",
+		"ai_policy_cleared":  "Restored the default AI policy",
+		"ai_policy_too_long": "The AI policy can't be longer than %d characters",
+
+		// AI profile check
+		"help_cmd_toggle_ai_profile": "/toggle_ai_profile - Toggle the AI check of joining users' name, username and bio",
+		"settings_ai_profile":        "- AI Profile Check: %s",
+		"toggle_ai_profile":          "Toggle AI Profile Check",
+		"ai_profile_enabled":         "AI profile check enabled",
+		"ai_profile_disabled":        "AI profile check disabled",
+		"reason_ai_profile":          "Profile classified as a spam account by AI",
+		"warning_ai_rationale":       "AI rationale: <code>%s</code>",
+
+		// Flood detection
+		"help_cmd_flood":        "/flood - Set flood limits: messages, media and mentions per time window",
+		"settings_flood":        "- Flood Limits: %s",
+		"flood_limits":          "%s messages / %s media / %s mentions per %d seconds, then %s",
+		"flood_action_delete":   "delete the message",
+		"flood_action_restrict": "restrict the user",
+		"flood_action_mute":     "mute for %d minutes",
+		"flood_current":         "Current flood limits: %s",
+		"flood_usage":           "Usage:\n<code>/flood &lt;messages&gt; &lt;media&gt; &lt;mentions&gt; [window seconds]</code> set the limits, 0 means no limit\n<code>/flood action delete|restrict|mute [minutes]</code> set what happens when a limit is exceeded\n<code>/flood off</code> turn off flood detection\n\nExample: <code>/flood 10 5 10 10</code>",
+		"reason_flood":          "Flooding",
+		"warning_muted":         "User %s has been muted for %d minutes",
+
+		// Cross-group duplicate detection
+		"help_cmd_duplicates": "/duplicates - Set how many groups and users may post the same message",
+		"settings_duplicates": "- Cross-group Duplicates: %s",
+		"duplicates_limits":   "at most %s groups / %s users",
+		"duplicates_current":  "Current duplicate limits: %s",
+		"duplicates_usage":    "Usage:\n<code>/duplicates &lt;groups&gt; &lt;users&gt;</code> the same text or media posted by more distinct groups or users within the time window is spam, every copy is deleted and the senders restricted, 0 means no limit\n<code>/duplicates off</code> turn off the detection\n\nExample: <code>/duplicates 3 3</code>",
+		"reason_duplicate":    "Posted the same message in several groups",
+
+		// Edited messages
+		"help_cmd_toggle_edit_scan": "/toggle_edit_scan - Toggle the content check of edited messages",
+		"help_cmd_edit_window":      "/edit_window - Set the edit window, later edits are deleted",
+		"settings_edit_scan":        "- Edited Message Check: %s, Edit Window: %s",
+		"toggle_edit_scan":          "Toggle Edit Check",
+		"change_edit_window":        "Set Edit Window",
+		"edit_scan_enabled":         "Edited message check enabled",
+		"edit_scan_disabled":        "Edited message check disabled",
+		"select_edit_window":        "Select the edit window, messages edited later than this after posting will be deleted:",
+		"edit_window_updated":       "Edit window updated to %d minutes (0 means no limit)",
+		"minutes":                   "minutes",
+		"warning_edited":            "<i>The message was flagged after an edit</i>",
+		"reason_late_edit":          "Edited a message after the edit window",
+
+		// Inline bots
+		"help_cmd_toggle_inline_bots": "/toggle_inline_bots - Toggle deleting messages sent through inline bots that are not allowed",
+		"help_cmd_inline_bot_allow":   "/inline_bot_allow - Manage the allowed inline bots (add|del|list)",
+		"settings_inline_bots":        "- Block Inline Bots: %s",
+		"toggle_inline_bots":          "Toggle Inline Bot Blocking",
+		"inline_bots_enabled":         "Inline bot blocking enabled, messages via bots not on the allow list will be deleted",
+		"inline_bots_disabled":        "Inline bot blocking disabled",
+		"reason_inline_bot":           "Sent a message through an inline bot that is not allowed",
+
+		// Forward policies
+		"help_cmd_forward_channels":       "/forward_channels - Switch the channel forward policy: allow, block all or allow only listed channels",
+		"help_cmd_forward_channel_action": "/forward_channel_action - Switch blocked channel forwards between delete and delete and restrict",
+		"help_cmd_forward_allow":          "/forward_allow - Manage the channels forwards are allowed from (add|del|list)",
+		"help_cmd_toggle_hidden_forwards": "/toggle_hidden_forwards - Toggle blocking forwards from users who hide their account",
+		"help_cmd_hidden_forward_action":  "/hidden_forward_action - Switch blocked hidden-user forwards between delete and delete and restrict",
+		"settings_forward_channels":       "- Channel Forwards: %s, %s",
+		"settings_hidden_forwards":        "- Block Hidden-User Forwards: %s, %s",
+		"forward_channels_allow":          "allowed",
+		"forward_channels_block":          "blocked",
+		"forward_channels_allowlist":      "listed channels only",
+		"change_forward_channels":         "Change Channel Forward Policy",
+		"change_forward_channel_action":   "Toggle Channel Forward Action",
+		"toggle_hidden_forwards":          "Toggle Hidden-User Forwards",
+		"change_hidden_forward_action":    "Toggle Hidden-User Forward Action",
+		"forward_channels_changed":        "Channel forward policy set to: %s",
+		"forward_action_changed":          "Blocked forwards will: %s",
+		"hidden_forwards_enabled":         "Forwards from hidden users will be blocked",
+		"hidden_forwards_disabled":        "Forwards from hidden users are allowed",
+		"reason_forward_channel":          "Forwarded a message from a channel that is not allowed",
+		"reason_forward_hidden":           "Forwarded a message from a user who hides their account",
+		"group_list_values_forward":       "A value is the numeric ID of a channel, e.g. -1001234567890",
+
+		// Link domains
+		"help_cmd_domain_allow":    "/domain_allow - Manage the allowed link destinations (add|del|list)",
+		"help_cmd_domain_deny":     "/domain_deny - Manage the denied link domains, including those a shortened link redirects through (add|del|list)",
+		"group_list_values_domain": "A value is a domain, e.g. example.com, which also matches its subdomains",
+		"reason_url_domain":        "Posted a link leading to a denied domain",
+
+		// Invisible characters
+		"help_cmd_toggle_invisible_chars": "/toggle_invisible_chars - Toggle the hidden characters in name check",
+		"settings_invisible_chars":        "- Hidden Characters in Name: %s",
+		"toggle_invisible_chars":          "Toggle Hidden Characters Check",
+		"invisible_chars_enabled":         "Hidden characters check enabled, the signal needs another one to reach the restrict score",
+		"invisible_chars_disabled":        "Hidden characters check disabled",
+
+		// Score threshold checks
+		"restrict_score_not_below_ban": "The restrict threshold must be below the ban threshold (%d)",
+		"ban_score_not_above_restrict": "The ban threshold must be above the restrict threshold (%d), or choose Never",
 	},
 }
 
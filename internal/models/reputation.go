@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ReputationEntry is the cached verdict of a blocklist provider for a user.
+// Listed users and clean users are cached with separate TTLs.
+type ReputationEntry struct {
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false"`
+	Provider  string    `gorm:"primaryKey;size:64"`
+	Listed    bool      `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+	UpdatedAt time.Time
+}
+
+// Expired reports whether the entry is no longer valid at now
+func (e *ReputationEntry) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
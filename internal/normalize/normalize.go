@@ -0,0 +1,105 @@
+// Package normalize folds look-alike text to a comparable skeleton form
+// so that name and bio checks can't be bypassed with homoglyphs,
+// mathematical alphanumerics, zero-width or bidi control characters.
+package normalize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps common Cyrillic, Greek and Armenian look-alikes to Latin.
+// Compatibility forms (fullwidth, mathematical, circled letters) are already folded by NFKC.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'ӏ': 'l',
+	'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'у': 'y', 'ԝ': 'w', 'х': 'x',
+	'А': 'A', 'В': 'B', 'С': 'C', 'Е': 'E', 'Н': 'H', 'І': 'I', 'Ј': 'J', 'К': 'K',
+	'М': 'M', 'О': 'O', 'Р': 'P', 'Ѕ': 'S', 'Т': 'T', 'Х': 'X', 'Ү': 'Y', 'Ԝ': 'W',
+	// Greek
+	'α': 'a', 'ι': 'i', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'υ': 'u',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+	// Armenian
+	'օ': 'o', 'ո': 'n', 'ս': 'u', 'հ': 'h', 'Ս': 'U', 'Օ': 'O',
+	// Latin
+	'ı': 'i', 'ɑ': 'a', 'ɡ': 'g', 'ʏ': 'y', 'ᴅ': 'd',
+}
+
+// invisibleRanges are zero-width, filler and bidi control characters
+var invisibleRanges = [][2]rune{
+	{0x00AD, 0x00AD}, // soft hyphen
+	{0x034F, 0x034F}, // combining grapheme joiner
+	{0x061C, 0x061C}, // arabic letter mark
+	{0x115F, 0x1160}, // hangul choseong/jungseong fillers
+	{0x180E, 0x180E}, // mongolian vowel separator
+	{0x200B, 0x200F}, // zero-width space/joiners, LRM, RLM
+	{0x202A, 0x202E}, // bidi embeddings and overrides
+	{0x2060, 0x2064}, // word joiner, invisible operators
+	{0x2066, 0x2069}, // bidi isolates
+	{0x3164, 0x3164}, // hangul filler
+	{0xFEFF, 0xFEFF}, // zero-width no-break space
+	{0xFFA0, 0xFFA0}, // halfwidth hangul filler
+}
+
+// IsInvisible reports whether r is a zero-width, filler or bidi control character
+func IsInvisible(r rune) bool {
+	for _, rng := range invisibleRanges {
+		if r >= rng[0] && r <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// StripInvisible removes invisible and bidi control characters
+func StripInvisible(s string) string {
+	return strings.Map(func(r rune) rune {
+		if IsInvisible(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Skeleton folds s to a comparable form: invisible characters are removed,
+// compatibility characters are decomposed (NFKD), combining marks are dropped
+// and known confusables are mapped to their Latin counterparts.
+func Skeleton(s string) string {
+	decomposed := norm.NFKD.String(StripInvisible(s))
+	folded := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		if latin, ok := confusables[r]; ok {
+			return latin
+		}
+		return r
+	}, decomposed)
+	return norm.NFC.String(folded)
+}
+
+// InvisibleChars returns the code points of invisible characters in s, e.g. ["U+202E"].
+// A zero-width joiner next to a symbol is part of an emoji sequence and is not reported.
+func InvisibleChars(s string) []string {
+	runes := []rune(s)
+	var found []string
+	for i, r := range runes {
+		if !IsInvisible(r) {
+			continue
+		}
+		if r == 0x200D && ((i > 0 && isEmojiPart(runes[i-1])) || (i+1 < len(runes) && isEmojiPart(runes[i+1]))) {
+			continue
+		}
+		found = append(found, fmt.Sprintf("U+%04X", r))
+	}
+	return found
+}
+
+// isEmojiPart reports whether r can be joined in an emoji ZWJ sequence
+func isEmojiPart(r rune) bool {
+	return unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) || r == 0xFE0F
+}
@@ -0,0 +1,188 @@
+// Package provider checks users against external and local blocklists.
+// Every provider is wrapped with a timeout, the persistent reputation cache
+// with separate TTLs for listed and clean users, and a circuit breaker that
+// skips it for a while after repeated failures. Local lookups bypass the
+// cache and the breaker, they are cheap and must reflect bans and unbans at once.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/service"
+)
+
+// BlocklistProvider reports whether a user is listed in a blocklist
+type BlocklistProvider interface {
+	// Name returns the unique name groups select the provider by
+	Name() string
+	// Check looks the user up, ctx carries the provider's timeout
+	Check(ctx context.Context, userID int64) (bool, error)
+}
+
+// LocalProvider is implemented by providers that may answer from local data, such as a database or a file.
+// While Local reports true, lookups are neither cached nor counted by the circuit breaker.
+type LocalProvider interface {
+	Local() bool
+}
+
+// Options controls the timeout, caching and circuit breaking of a provider
+type Options struct {
+	Timeout          time.Duration
+	PositiveTTL      time.Duration // how long a listed user is cached
+	NegativeTTL      time.Duration // how long a clean user is cached
+	FailureThreshold int           // consecutive failures that open the circuit
+	Cooldown         time.Duration // how long an open circuit skips the provider
+}
+
+// withDefaults fills unset options
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = 5 * time.Second
+	}
+	if o.PositiveTTL <= 0 {
+		o.PositiveTTL = 24 * time.Hour
+	}
+	if o.NegativeTTL <= 0 {
+		o.NegativeTTL = time.Hour
+	}
+	if o.FailureThreshold <= 0 {
+		o.FailureThreshold = 3
+	}
+	if o.Cooldown <= 0 {
+		o.Cooldown = time.Minute
+	}
+	return o
+}
+
+// guarded wraps a provider with timeout, caching and a circuit breaker
+type guarded struct {
+	provider BlocklistProvider
+	opts     Options
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// ErrCircuitOpen is returned while a provider is skipped after repeated failures
+var ErrCircuitOpen = fmt.Errorf("circuit open")
+
+func (g *guarded) check(userID int64) (bool, error) {
+	if local, ok := g.provider.(LocalProvider); ok && local.Local() {
+		ctx, cancel := context.WithTimeout(context.Background(), g.opts.Timeout)
+		defer cancel()
+		return g.provider.Check(ctx, userID)
+	}
+
+	name := g.provider.Name()
+	if listed, ok := service.GetReputation(userID, name); ok {
+		return listed, nil
+	}
+
+	g.mu.Lock()
+	if time.Now().Before(g.openUntil) {
+		g.mu.Unlock()
+		return false, ErrCircuitOpen
+	}
+	g.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.opts.Timeout)
+	defer cancel()
+	listed, err := g.provider.Check(ctx, userID)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err != nil {
+		g.failures++
+		if g.failures >= g.opts.FailureThreshold {
+			// half-open after the cooldown: the next lookup is let through and closes the circuit on success
+			g.openUntil = time.Now().Add(g.opts.Cooldown)
+			logger.Warningf("Blocklist provider %s failed %d times, skipping it for %v", name, g.failures, g.opts.Cooldown)
+		}
+		return false, err
+	}
+
+	g.failures = 0
+	g.openUntil = time.Time{}
+	ttl := g.opts.NegativeTTL
+	if listed {
+		ttl = g.opts.PositiveTTL
+	}
+	service.SetReputation(userID, name, listed, ttl)
+	return listed, nil
+}
+
+var (
+	providers   = make(map[string]*guarded)
+	providersMu sync.RWMutex
+)
+
+// Register adds a provider, replacing any provider with the same name
+func Register(provider BlocklistProvider, opts Options) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	providers[provider.Name()] = &guarded{
+		provider: provider,
+		opts:     opts.withDefaults(),
+	}
+}
+
+// Names returns the names of the registered providers in alphabetical order
+func Names() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check looks the user up in a single provider
+func Check(name string, userID int64) (bool, error) {
+	providersMu.RLock()
+	g, ok := providers[name]
+	providersMu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("unknown blocklist provider %q", name)
+	}
+	return g.check(userID)
+}
+
+// CheckAll looks the user up in the named providers concurrently and returns those that list the user.
+// Failing providers are logged and treated as not listing the user.
+func CheckAll(names []string, userID int64) []string {
+	listed := make([]bool, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hit, err := Check(name, userID)
+			if err != nil {
+				if err != ErrCircuitOpen {
+					logger.Warningf("Error checking user %d with blocklist provider %s: %v", userID, name, err)
+				}
+				return
+			}
+			listed[i] = hit
+		}()
+	}
+	wg.Wait()
+
+	var hits []string
+	for i, name := range names {
+		if listed[i] {
+			hits = append(hits, name)
+		}
+	}
+	return hits
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-antispam/internal/cas"
+	"tg-antispam/internal/config"
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/service"
+)
+
+// Provider types that can be configured in blocklist_providers
+const (
+	TypeCAS         = "cas"
+	TypeHTTPJSON    = "http_json"
+	TypeFile        = "file"
+	TypeBanDatabase = "ban_database"
+)
+
+// Initialize registers the configured providers, CAS and the bot's own ban database if none are configured
+func Initialize(cfgs []config.BlocklistProviderConfig) {
+	if len(cfgs) == 0 {
+		cfgs = []config.BlocklistProviderConfig{
+			{Name: "cas", Type: TypeCAS},
+			{Name: "own_bans", Type: TypeBanDatabase},
+		}
+	}
+
+	for _, cfg := range cfgs {
+		var p BlocklistProvider
+		switch cfg.Type {
+		case TypeCAS:
+			p = casProvider{name: cfg.Name}
+		case TypeHTTPJSON:
+			p = &httpJSONProvider{name: cfg.Name, url: cfg.URL, field: cfg.Field, client: http.DefaultClient}
+		case TypeFile:
+			p = &fileProvider{name: cfg.Name, path: cfg.Path}
+		case TypeBanDatabase:
+			p = banDatabaseProvider{name: cfg.Name}
+		default:
+			logger.Warningf("Unknown blocklist provider type %q for provider %s", cfg.Type, cfg.Name)
+			continue
+		}
+		if cfg.Name == "" {
+			logger.Warningf("Skipping blocklist provider of type %s without a name", cfg.Type)
+			continue
+		}
+
+		Register(p, Options{
+			Timeout:          time.Duration(cfg.TimeoutSeconds) * time.Second,
+			PositiveTTL:      time.Duration(cfg.PositiveTTLMinutes) * time.Minute,
+			NegativeTTL:      time.Duration(cfg.NegativeTTLMinutes) * time.Minute,
+			FailureThreshold: cfg.FailureThreshold,
+			Cooldown:         time.Duration(cfg.CooldownSeconds) * time.Second,
+		})
+		logger.Infof("Registered blocklist provider %s (%s)", cfg.Name, cfg.Type)
+	}
+}
+
+// casProvider checks the Combot Anti-Spam System through the local mirror or the live API
+type casProvider struct {
+	name string
+}
+
+func (p casProvider) Name() string { return p.name }
+
+// Local reports whether the mirror answers, the live API fallback goes through the cache
+func (p casProvider) Local() bool { return cas.MirrorLoaded() }
+
+func (p casProvider) Check(ctx context.Context, userID int64) (bool, error) {
+	return cas.Check(ctx, userID)
+}
+
+// httpJSONProvider queries a JSON endpoint, "{user_id}" in the URL is replaced with the user ID.
+// The user is listed if the value at the dotted field path is true or a positive number.
+type httpJSONProvider struct {
+	name   string
+	url    string
+	field  string
+	client *http.Client
+}
+
+func (p *httpJSONProvider) Name() string { return p.name }
+
+func (p *httpJSONProvider) Check(ctx context.Context, userID int64) (bool, error) {
+	url := strings.ReplaceAll(p.url, "{user_id}", strconv.FormatInt(userID, 10))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%s returned status code %d", p.name, resp.StatusCode)
+	}
+
+	var body interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, err
+	}
+	return jsonTruthy(jsonField(body, p.field)), nil
+}
+
+// jsonField walks a dotted path such as "result.banned" through decoded JSON objects
+func jsonField(value interface{}, path string) interface{} {
+	if path == "" {
+		return value
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+func jsonTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v > 0
+	case string:
+		return v == "true" || v == "1"
+	}
+	return false
+}
+
+// fileProvider checks a local CSV of user IDs, reloaded when the file changes
+type fileProvider struct {
+	name string
+	path string
+
+	mu        sync.Mutex
+	mirror    *cas.Mirror
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// fileCheckInterval is how often the file's modification time is compared
+const fileCheckInterval = 30 * time.Second
+
+func (p *fileProvider) Name() string { return p.name }
+func (p *fileProvider) Local() bool  { return true }
+
+func (p *fileProvider) Check(ctx context.Context, userID int64) (bool, error) {
+	if err := p.reload(ctx); err != nil {
+		return false, err
+	}
+	return p.mirror.Contains(userID), nil
+}
+
+// reload reads the file again if it was modified since the last load
+func (p *fileProvider) reload(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.mirror != nil && time.Since(p.checkedAt) < fileCheckInterval {
+		return nil
+	}
+	p.checkedAt = time.Now()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	if p.mirror != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	mirror := cas.NewMirror(p.path, nil)
+	if err := mirror.Load(ctx); err != nil {
+		return err
+	}
+	p.mirror, p.modTime = mirror, info.ModTime()
+	logger.Infof("Loaded %d user IDs for blocklist provider %s from %s", mirror.Len(), p.name, p.path)
+	return nil
+}
+
+// banDatabaseProvider lists users the bot has banned in any group
+type banDatabaseProvider struct {
+	name string
+}
+
+func (p banDatabaseProvider) Name() string { return p.name }
+func (p banDatabaseProvider) Local() bool  { return true }
+
+func (p banDatabaseProvider) Check(ctx context.Context, userID int64) (bool, error) {
+	records, err := service.GetUserActiveBanRecords(userID, -1)
+	if err != nil {
+		return false, err
+	}
+	for _, record := range records {
+		if record.Action == "ban" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,123 @@
+package rules
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+)
+
+// Actions applied to accounts younger than the group's minimum account age
+const (
+	AccountAgeActionRestrict  = "restrict"
+	AccountAgeActionChallenge = "challenge"
+)
+
+// accountAgeData holds user ID to creation date anchors
+//
+//go:embed data/account_age_anchors.txt
+var accountAgeData string
+
+type accountAgeAnchor struct {
+	userID  int64
+	created time.Time
+}
+
+var (
+	accountAgeAnchors     []accountAgeAnchor
+	accountAgeAnchorsOnce sync.Once
+)
+
+func init() {
+	Register(accountAgeRule{})
+}
+
+func loadAccountAgeAnchors() []accountAgeAnchor {
+	accountAgeAnchorsOnce.Do(func() {
+		scanner := bufio.NewScanner(strings.NewReader(accountAgeData))
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 2 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			userID, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			created, err := time.Parse("2006-01-02", fields[1])
+			if err != nil {
+				continue
+			}
+			accountAgeAnchors = append(accountAgeAnchors, accountAgeAnchor{userID: userID, created: created})
+		}
+		sort.Slice(accountAgeAnchors, func(i, j int) bool {
+			return accountAgeAnchors[i].userID < accountAgeAnchors[j].userID
+		})
+		logger.Debugf("Loaded %d account age anchors", len(accountAgeAnchors))
+	})
+	return accountAgeAnchors
+}
+
+// EstimateAccountCreation estimates when the account with the given user ID was created.
+// IDs between anchors are interpolated, IDs newer than the last anchor are extrapolated up to now.
+func EstimateAccountCreation(userID int64) time.Time {
+	anchors := loadAccountAgeAnchors()
+	if len(anchors) == 0 {
+		return time.Time{}
+	}
+	if len(anchors) == 1 || userID <= anchors[0].userID {
+		return anchors[0].created
+	}
+
+	i := sort.Search(len(anchors), func(i int) bool { return anchors[i].userID >= userID })
+	if i == len(anchors) {
+		// extrapolate with the rate of the last segment
+		i = len(anchors) - 1
+	}
+	lo, hi := anchors[i-1], anchors[i]
+	ratio := float64(userID-lo.userID) / float64(hi.userID-lo.userID)
+	estimate := lo.created.Add(time.Duration(ratio * float64(hi.created.Sub(lo.created))))
+	if now := time.Now(); estimate.After(now) {
+		return now
+	}
+	return estimate
+}
+
+// EstimateAccountAgeDays returns the estimated age of the account in days
+func EstimateAccountAgeDays(userID int64) int {
+	return int(time.Since(EstimateAccountCreation(userID)).Hours() / 24)
+}
+
+// accountAgeRule restricts or challenges accounts younger than the group's minimum age
+type accountAgeRule struct{}
+
+func (accountAgeRule) ID() string                           { return "account_age" }
+func (accountAgeRule) Order() int                           { return 60 }
+func (accountAgeRule) Events() Event                        { return EventJoin }
+func (accountAgeRule) Enabled(group *models.GroupInfo) bool { return group.MinAccountAgeDays > 0 }
+
+func (r accountAgeRule) Evaluate(subject *Subject) Result {
+	created := EstimateAccountCreation(subject.User.ID)
+	days := int(time.Since(created).Hours() / 24)
+	if days >= subject.Group.MinAccountAgeDays {
+		return pass(r.ID())
+	}
+
+	verdict := VerdictRestrict
+	if subject.Group.AccountAgeAction == AccountAgeActionChallenge {
+		verdict = VerdictChallenge
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  verdict,
+		Reason:   "reason_young_account",
+		Evidence: fmt.Sprintf("created ~%s (%d days)", created.Format("2006-01-02"), days),
+	}
+}
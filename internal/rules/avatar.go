@@ -0,0 +1,123 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/imagehash"
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// AvatarHashMaxDistance is the largest Hamming distance at which two avatars are considered the same picture
+const AvatarHashMaxDistance = 6
+
+func init() {
+	Register(avatarHashRule{})
+	Register(noAvatarRule{})
+}
+
+// FetchAvatarHash downloads the user's current profile photo and returns its perceptual hash.
+// hasPhoto is false if the user has no (visible) profile photo.
+func FetchAvatarHash(bot *telego.Bot, userID int64) (hash uint64, hasPhoto bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	photos, err := bot.GetUserProfilePhotos(ctx, &telego.GetUserProfilePhotosParams{
+		UserID: userID,
+		Limit:  1,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if photos.TotalCount == 0 || len(photos.Photos) == 0 || len(photos.Photos[0]) == 0 {
+		return 0, false, nil
+	}
+
+	// the smallest size is enough for a perceptual hash
+	file, err := bot.GetFile(ctx, &telego.GetFileParams{FileID: photos.Photos[0][0].FileID})
+	if err != nil {
+		return 0, true, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bot.FileDownloadURL(file.FilePath), nil)
+	if err != nil {
+		return 0, true, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, true, fmt.Errorf("download profile photo: status code %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return 0, true, err
+	}
+	hash, err = imagehash.Decode(data)
+	return hash, true, err
+}
+
+// MatchSpamAvatar returns the distance to the closest known spam avatar of the group, false if none is close enough
+func MatchSpamAvatar(groupID int64, hash uint64) (int, bool) {
+	best := -1
+	for _, known := range service.GetAvatarHashes(groupID) {
+		if distance := imagehash.Distance(hash, known); best < 0 || distance < best {
+			best = distance
+		}
+	}
+	return best, best >= 0 && best <= AvatarHashMaxDistance
+}
+
+// avatarHashRule flags users whose profile photo matches a known spam avatar
+type avatarHashRule struct{}
+
+func (avatarHashRule) ID() string                           { return "avatar_hash" }
+func (avatarHashRule) Order() int                           { return 45 }
+func (avatarHashRule) Events() Event                        { return EventJoin }
+func (avatarHashRule) Enabled(group *models.GroupInfo) bool { return group.BanAvatarHash }
+
+func (r avatarHashRule) Evaluate(subject *Subject) Result {
+	hash, hasPhoto, err := subject.Avatar()
+	if err != nil {
+		logger.Warningf("Error getting profile photo of user %d: %v", subject.User.ID, err)
+		return pass(r.ID())
+	}
+	if !hasPhoto {
+		return pass(r.ID())
+	}
+	distance, ok := MatchSpamAvatar(subject.Group.GroupID, hash)
+	if !ok {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScoreAvatarHash, "reason_spam_avatar", fmt.Sprintf("phash %016x, distance %d", hash, distance))
+}
+
+// noAvatarRule flags users without a profile photo
+type noAvatarRule struct{}
+
+func (noAvatarRule) ID() string                           { return "no_avatar" }
+func (noAvatarRule) Order() int                           { return 46 }
+func (noAvatarRule) Events() Event                        { return EventJoin }
+func (noAvatarRule) Enabled(group *models.GroupInfo) bool { return group.BanNoAvatar }
+
+func (r noAvatarRule) Evaluate(subject *Subject) Result {
+	_, hasPhoto, err := subject.Avatar()
+	if err != nil && !hasPhoto {
+		logger.Warningf("Error getting profile photo of user %d: %v", subject.User.ID, err)
+		return pass(r.ID())
+	}
+	if hasPhoto {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScoreNoAvatar, "reason_no_avatar", "no profile photo")
+}
@@ -0,0 +1,51 @@
+package rules
+
+import (
+	"fmt"
+
+	"tg-antispam/internal/bayes"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// BayesRuleID identifies the naive Bayes rule, its verdicts are not fed back into the model
+const BayesRuleID = "bayes"
+
+// BayesSpamThreshold is the spam probability from which a message is flagged
+const BayesSpamThreshold = 0.9
+
+// bayesMinTokens is the number of tokens a message needs before it is classified
+const bayesMinTokens = 3
+
+func init() {
+	Register(bayesRule{})
+}
+
+// bayesRule classifies messages with the local naive Bayes model
+type bayesRule struct{}
+
+func (bayesRule) ID() string    { return BayesRuleID }
+func (bayesRule) Order() int    { return 65 }
+func (bayesRule) Events() Event { return EventMessage | EventEdit }
+func (bayesRule) Costly() bool  { return true }
+
+func (bayesRule) Enabled(group *models.GroupInfo) bool {
+	return group.EnableMessageScan && group.EnableBayes
+}
+
+func (r bayesRule) Evaluate(subject *Subject) Result {
+	text := subject.Text()
+	if len(bayes.Tokenize(text)) < bayesMinTokens {
+		return pass(r.ID())
+	}
+	probability, ok := service.BayesSpamProbability(text)
+	if !ok || probability < BayesSpamThreshold {
+		return pass(r.ID())
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  MessageVerdict(subject.Group),
+		Reason:   ReasonBayesSpam,
+		Evidence: fmt.Sprintf("p=%.2f", probability),
+	}
+}
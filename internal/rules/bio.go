@@ -0,0 +1,199 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+	"tg-antispam/internal/service"
+)
+
+// Kinds of entities extracted from a bio
+const (
+	BioEntityTelegramLink = "tme_link"
+	BioEntityInvite       = "tme_invite"
+	BioEntityMention      = "mention"
+	BioEntityDomain       = "domain"
+	BioEntityPhone        = "phone"
+	BioEntityBTC          = "btc"
+	BioEntityETH          = "eth"
+	BioEntityTRON         = "tron"
+)
+
+// BioEntity is a typed value found in a bio, Value is normalized so it can be compared with list entries:
+// "@username" for links and mentions, "+hash" for invite links (case-sensitive, a legacy joinchat/hash link is the same invite),
+// the lowercase host for domains, "+digits" for phone numbers
+type BioEntity struct {
+	Kind  string
+	Value string
+}
+
+// String formats the entity for evidence, e.g. "domain:example.com"
+func (e BioEntity) String() string {
+	return e.Kind + ":" + e.Value
+}
+
+var (
+	telegramLinkRegex = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?(?:t\.me|telegram\.me|telegram\.dog)/(?:s/)?([a-z0-9_]{4,32})`)
+	inviteLinkRegex   = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?(?:t\.me|telegram\.me|telegram\.dog)/(?:joinchat/|\+)([a-z0-9_-]{8,64})`)
+	mentionRegex      = regexp.MustCompile(`(?:^|[^\w@./])@([a-zA-Z][a-zA-Z0-9_]{3,31})`)
+	urlRegex          = regexp.MustCompile(`(?i)\b(?:(?:https?://|www\.)((?:[a-z0-9-]+\.)+[a-z]{2,24})|((?:[a-z0-9-]+\.)+(?:com|net|org|io|me|co|cc|xyz|top|info|biz|ru|cn|ly|gg|link|site|online|shop|store|app|vip|club|pro|live|fun|bet|tk|ml|ga|cf|gq|ws|to|in|us|uk)))\b`)
+	phoneRegex        = regexp.MustCompile(`\+?\d[\d\s().-]{6,18}\d`)
+	btcRegex          = regexp.MustCompile(`\b(?:bc1[ac-hj-np-z02-9]{25,59}|[13][a-km-zA-HJ-NP-Z1-9]{25,34})\b`)
+	ethRegex          = regexp.MustCompile(`\b0x[a-fA-F0-9]{40}\b`)
+	tronRegex         = regexp.MustCompile(`\bT[1-9A-HJ-NP-Za-km-z]{33}\b`)
+	// dates like 1990-01-01 or 01.01.1990, a plain number containing one is not a phone number
+	dateRegex = regexp.MustCompile(`\b(?:\d{4}[-./]\d{1,2}[-./]\d{1,2}|\d{1,2}[-./]\d{1,2}[-./]\d{4})\b`)
+
+	// hosts of Telegram links, reported as links instead of domains
+	telegramHosts = []string{"t.me", "telegram.me", "telegram.dog"}
+)
+
+// AnalyzeBio extracts Telegram links, mentions, domains, phone numbers and crypto wallet addresses from a bio.
+// Each distinct entity is reported once.
+func AnalyzeBio(bio string) []BioEntity {
+	if bio == "" {
+		return nil
+	}
+	text := normalize.Skeleton(bio)
+
+	var entities []BioEntity
+	seen := make(map[BioEntity]bool)
+	add := func(kind, value string) {
+		entity := BioEntity{Kind: kind, Value: value}
+		if !seen[entity] {
+			seen[entity] = true
+			entities = append(entities, entity)
+		}
+	}
+
+	// invite hashes are removed afterwards so their digits are not taken for a phone number
+	for _, m := range inviteLinkRegex.FindAllStringSubmatch(text, -1) {
+		add(BioEntityInvite, "+"+m[1])
+		text = strings.ReplaceAll(text, m[0], " ")
+	}
+	for _, m := range telegramLinkRegex.FindAllStringSubmatch(text, -1) {
+		// t.me/joinchat/hash is an invite link, not the username "joinchat"
+		if username := strings.ToLower(m[1]); username != "joinchat" {
+			add(BioEntityTelegramLink, "@"+username)
+		}
+	}
+	for _, m := range mentionRegex.FindAllStringSubmatch(text, -1) {
+		add(BioEntityMention, "@"+strings.ToLower(m[1]))
+	}
+	for _, m := range urlRegex.FindAllStringSubmatch(text, -1) {
+		host := strings.ToLower(m[1] + m[2])
+		host = strings.TrimPrefix(host, "www.")
+		if !containsDomain(telegramHosts, host) {
+			add(BioEntityDomain, host)
+		}
+	}
+
+	// wallets are matched before phone numbers so their digits are not mistaken for one
+	for _, wallet := range []struct {
+		kind string
+		re   *regexp.Regexp
+	}{{BioEntityETH, ethRegex}, {BioEntityTRON, tronRegex}, {BioEntityBTC, btcRegex}} {
+		for _, address := range wallet.re.FindAllString(text, -1) {
+			add(wallet.kind, address)
+			text = strings.ReplaceAll(text, address, " ")
+		}
+	}
+	for _, number := range phoneRegex.FindAllString(text, -1) {
+		if phone, ok := normalizePhone(number); ok {
+			add(BioEntityPhone, phone)
+		}
+	}
+	return entities
+}
+
+// normalizePhone reduces a phone number to "+digits", plain numbers need at least 10 digits
+// and must not contain a date so that dates and years are not taken for phone numbers
+func normalizePhone(s string) (string, bool) {
+	if !strings.HasPrefix(s, "+") && dateRegex.MatchString(s) {
+		return "", false
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if len(digits) < 8 || len(digits) > 15 || (!strings.HasPrefix(s, "+") && len(digits) < 10) {
+		return "", false
+	}
+	return "+" + digits, true
+}
+
+// NormalizeBioListValue converts a user supplied allow/deny list value to the form of BioEntity.Value,
+// e.g. "https://t.me/Channel" to "@channel" and "https://www.Example.com/x" to "example.com"
+func NormalizeBioListValue(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+	if strings.HasPrefix(value, "@") {
+		value = " " + value
+	}
+	entities := AnalyzeBio(value)
+	if len(entities) != 1 {
+		return "", false
+	}
+	return entities[0].Value, true
+}
+
+// containsDomain reports whether host is one of the domains or a subdomain of one
+func containsDomain(domains []string, host string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesBioList reports whether an entity is in one of the group's bio lists
+func matchesBioList(values []string, entity BioEntity) bool {
+	if entity.Kind == BioEntityDomain {
+		return containsDomain(values, entity.Value)
+	}
+	return containsString(values, entity.Value)
+}
+
+// SuspiciousBioEntities analyzes a bio and applies the group's allow and deny lists.
+// Mentions of ownUsernames (the user and their personal channel) and allowed entities are dropped;
+// denied holds the entities on the deny list, suspicious the remaining ones.
+func SuspiciousBioEntities(group *models.GroupInfo, bio string, ownUsernames []string) (suspicious, denied []BioEntity) {
+	allow := service.GetGroupList(group.GroupID, models.GroupListBioAllow)
+	deny := service.GetGroupList(group.GroupID, models.GroupListBioDeny)
+	for _, entity := range AnalyzeBio(bio) {
+		switch {
+		case matchesBioList(deny, entity):
+			denied = append(denied, entity)
+		case matchesBioList(allow, entity):
+		case (entity.Kind == BioEntityMention || entity.Kind == BioEntityTelegramLink) && containsString(ownUsernames, entity.Value):
+		default:
+			suspicious = append(suspicious, entity)
+		}
+	}
+	return suspicious, denied
+}
+
+// FormatBioEntities joins entities for evidence, e.g. "mention:@spam, domain:bit.ly"
+func FormatBioEntities(entities []BioEntity) string {
+	parts := make([]string, len(entities))
+	for i, entity := range entities {
+		parts[i] = entity.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"fmt"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+	"tg-antispam/internal/service"
+)
+
+func init() {
+	Register(blocklistRule{})
+}
+
+// blocklistRule matches the per-group custom patterns managed with /blocklist.
+// Name, username and bio patterns are checked at join, message text patterns on every message and edit.
+type blocklistRule struct{}
+
+func (blocklistRule) ID() string                           { return "blocklist" }
+func (blocklistRule) Order() int                           { return 5 }
+func (blocklistRule) Events() Event                        { return EventJoin | EventMessage | EventEdit }
+func (blocklistRule) Enabled(group *models.GroupInfo) bool { return true }
+
+func (r blocklistRule) Evaluate(subject *Subject) Result {
+	result := pass(r.ID())
+	for _, pattern := range service.GetBlocklistPatterns(subject.Group.GroupID) {
+		value, ok := blocklistFieldValue(subject, pattern.Field)
+		if !ok || !(pattern.Match(value) || pattern.Match(normalize.Skeleton(value))) {
+			continue
+		}
+
+		verdict := blocklistVerdict(pattern.Action, subject.Event)
+		if verdict <= result.Verdict {
+			continue
+		}
+		result = Result{
+			RuleID:   r.ID(),
+			Verdict:  verdict,
+			Reason:   "reason_blocklist",
+			Evidence: fmt.Sprintf("#%d %s %s %q", pattern.ID, pattern.Field, pattern.MatchType, pattern.Pattern),
+		}
+		if verdict == VerdictBan {
+			break
+		}
+	}
+	return result
+}
+
+// blocklistFieldValue returns the value of the field for the event, false if it is not checked
+func blocklistFieldValue(subject *Subject, field string) (string, bool) {
+	if field == models.BlocklistFieldMessageText {
+		if subject.Event == EventJoin || subject.Message == nil {
+			return "", false
+		}
+		return subject.Text(), true
+	}
+
+	if subject.Event != EventJoin {
+		return "", false
+	}
+	switch field {
+	case models.BlocklistFieldFirstName:
+		return subject.User.FirstName, true
+	case models.BlocklistFieldLastName:
+		return subject.User.LastName, true
+	case models.BlocklistFieldUsername:
+		return subject.User.Username, true
+	case models.BlocklistFieldBio:
+		bio, err := subject.Bio()
+		if err != nil {
+			logger.Warningf("Error getting chat info for user %d: %v", subject.User.ID, err)
+			return "", false
+		}
+		return bio, true
+	}
+	return "", false
+}
+
+// blocklistVerdict maps a pattern action to a verdict.
+// There is no message to delete at join, so delete restricts the member instead.
+func blocklistVerdict(action string, event Event) Verdict {
+	switch action {
+	case models.BlocklistActionBan:
+		return VerdictBan
+	case models.BlocklistActionRestrict:
+		return VerdictRestrict
+	case models.BlocklistActionDelete:
+		if event == EventJoin {
+			return VerdictRestrict
+		}
+		return VerdictDelete
+	}
+	return VerdictPass
+}
@@ -0,0 +1,241 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+	"tg-antispam/internal/provider"
+	"tg-antispam/internal/service"
+)
+
+// Default scores of the built-in signals, can be overridden with antispam.rule_scores
+const (
+	ScorePremium        = 30
+	ScoreEmojiName      = 30
+	ScoreRandomUsername = 30
+	ScoreBioLink        = 40
+	ScoreBioDeny        = 100
+	ScoreInvisibleChars = 15
+	ScoreAvatarHash     = 70
+	ScoreNoAvatar       = 20
+	ScoreProvider       = 100
+	ScoreAISpam         = 70
+	ScoreImpersonation  = 20
+)
+
+func init() {
+	Register(premiumRule{})
+	Register(invisibleCharsRule{})
+	Register(emojiNameRule{})
+	Register(randomUsernameRule{})
+	Register(bioLinkRule{})
+	Register(bioDenyRule{})
+	Register(blocklistProviderRule{})
+}
+
+// premiumRule flags Telegram Premium users
+type premiumRule struct{}
+
+func (premiumRule) ID() string                           { return "premium_user" }
+func (premiumRule) Order() int                           { return 10 }
+func (premiumRule) Events() Event                        { return EventJoin }
+func (premiumRule) Enabled(group *models.GroupInfo) bool { return group.BanPremium }
+
+func (r premiumRule) Evaluate(subject *Subject) Result {
+	if !subject.User.IsPremium {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScorePremium, "reason_premium_user", "premium")
+}
+
+// invisibleCharsRule flags names hiding zero-width or bidi control characters
+type invisibleCharsRule struct{}
+
+func (invisibleCharsRule) ID() string                           { return "invisible_chars" }
+func (invisibleCharsRule) Order() int                           { return 15 }
+func (invisibleCharsRule) Events() Event                        { return EventJoin }
+func (invisibleCharsRule) Enabled(group *models.GroupInfo) bool { return group.BanInvisibleChars }
+
+func (r invisibleCharsRule) Evaluate(subject *Subject) Result {
+	chars := normalize.InvisibleChars(subject.User.FirstName + subject.User.LastName)
+	if len(chars) == 0 {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScoreInvisibleChars, "reason_invisible_chars", strings.Join(chars, " "))
+}
+
+// emojiNameRule flags users whose name exceeds the group's emoji policy
+type emojiNameRule struct{}
+
+func (emojiNameRule) ID() string                           { return "emoji_name" }
+func (emojiNameRule) Order() int                           { return 20 }
+func (emojiNameRule) Events() Event                        { return EventJoin }
+func (emojiNameRule) Enabled(group *models.GroupInfo) bool { return group.BanEmojiName }
+
+// FullName returns the user's first and last name as the emoji name check sees them
+func FullName(user telego.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+func (r emojiNameRule) Evaluate(subject *Subject) Result {
+	name := FullName(subject.User)
+	if !ExceedsEmojiPolicy(subject.Group, name) {
+		return pass(r.ID())
+	}
+	emoji, letters := EmojiStats(name)
+	return signal(r.ID(), ScoreEmojiName, "reason_emoji_name", fmt.Sprintf("%s (emoji=%d, letters=%d)", name, emoji, letters))
+}
+
+// randomUsernameRule flags usernames that look randomly generated
+type randomUsernameRule struct{}
+
+func (randomUsernameRule) ID() string                           { return "random_username" }
+func (randomUsernameRule) Order() int                           { return 30 }
+func (randomUsernameRule) Events() Event                        { return EventJoin }
+func (randomUsernameRule) Enabled(group *models.GroupInfo) bool { return group.BanRandomUsername }
+
+func (r randomUsernameRule) Evaluate(subject *Subject) Result {
+	if !ExceedsRandomUsernameThreshold(subject.Group, subject.User.Username) {
+		return pass(r.ID())
+	}
+	probability := RandomUsernameProbability(subject.User.Username)
+	return signal(r.ID(), ScoreRandomUsername, "reason_random_username", fmt.Sprintf("@%s (p=%.2f)", subject.User.Username, probability))
+}
+
+// bioLinkRule flags users with links, mentions, phone numbers or wallets in their bio
+// that are neither their own nor on the group's allow list
+type bioLinkRule struct{}
+
+func (bioLinkRule) ID() string                           { return "bio_link" }
+func (bioLinkRule) Order() int                           { return 40 }
+func (bioLinkRule) Events() Event                        { return EventJoin }
+func (bioLinkRule) Enabled(group *models.GroupInfo) bool { return group.BanBioLink }
+
+func (r bioLinkRule) Evaluate(subject *Subject) Result {
+	profile, err := subject.Profile()
+	if err != nil {
+		logger.Warningf("Error getting chat info for user %d: %v", subject.User.ID, err)
+		return pass(r.ID())
+	}
+	suspicious, _ := SuspiciousBioEntities(subject.Group, profile.Bio, OwnUsernames(subject.User, profile))
+	if len(suspicious) == 0 {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScoreBioLink, "reason_bio_link", FormatBioEntities(suspicious))
+}
+
+// bioDenyRule flags users whose bio contains an entity on the group's deny list
+type bioDenyRule struct{}
+
+func (bioDenyRule) ID() string                           { return "bio_deny" }
+func (bioDenyRule) Order() int                           { return 41 }
+func (bioDenyRule) Events() Event                        { return EventJoin }
+func (bioDenyRule) Enabled(group *models.GroupInfo) bool { return true }
+
+func (r bioDenyRule) Evaluate(subject *Subject) Result {
+	if len(service.GetGroupList(subject.Group.GroupID, models.GroupListBioDeny)) == 0 {
+		return pass(r.ID())
+	}
+	profile, err := subject.Profile()
+	if err != nil {
+		return pass(r.ID())
+	}
+	_, denied := SuspiciousBioEntities(subject.Group, profile.Bio, OwnUsernames(subject.User, profile))
+	if len(denied) == 0 {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScoreBioDeny, "reason_bio_denied", FormatBioEntities(denied))
+}
+
+// blocklistProviderRule flags users listed by any of the group's blocklist providers
+type blocklistProviderRule struct{}
+
+func (blocklistProviderRule) ID() string    { return "blocklist_provider" }
+func (blocklistProviderRule) Order() int    { return 50 }
+func (blocklistProviderRule) Events() Event { return EventJoin }
+
+func (blocklistProviderRule) Enabled(group *models.GroupInfo) bool {
+	return group.EnableCAS || group.BlocklistProviders != ""
+}
+
+func (r blocklistProviderRule) Evaluate(subject *Subject) Result {
+	hits, reason := listedByProviders(subject.Group, subject.User.ID)
+	if len(hits) == 0 {
+		return pass(r.ID())
+	}
+	return signal(r.ID(), ScoreProvider, reason, strings.Join(hits, ", "))
+}
+
+// listedByProviders returns the group's blocklist providers that list the user and the matching reason
+func listedByProviders(group *models.GroupInfo, userID int64) ([]string, string) {
+	var names []string
+	for _, name := range provider.Names() {
+		if group.UsesBlocklistProvider(name) {
+			names = append(names, name)
+		}
+	}
+
+	hits := provider.CheckAll(names, userID)
+	if containsString(hits, models.CASProviderName) {
+		return hits, "reason_cas_blacklisted"
+	}
+	return hits, "reason_provider_blacklisted"
+}
+
+// GetUserProfile fetches the full chat info of a user, including bio and personal channel
+func GetUserProfile(bot *telego.Bot, userID int64) (*telego.ChatFullInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return bot.GetChat(ctx, &telego.GetChatParams{
+		ChatID: telego.ChatID{ID: userID},
+	})
+}
+
+// GetUserBio fetches the bio of a user through GetChat
+func GetUserBio(bot *telego.Bot, userID int64) (string, error) {
+	profile, err := GetUserProfile(bot, userID)
+	if err != nil {
+		return "", err
+	}
+	return profile.Bio, nil
+}
+
+// OwnUsernames returns the usernames of the user and their personal channel as "@username"
+func OwnUsernames(user telego.User, profile *telego.ChatFullInfo) []string {
+	usernames := []string{user.Username}
+	if profile != nil {
+		usernames = append(usernames, profile.ActiveUsernames...)
+		if profile.PersonalChat != nil {
+			usernames = append(usernames, profile.PersonalChat.Username)
+		}
+	}
+
+	var own []string
+	for _, username := range usernames {
+		if username != "" {
+			own = append(own, "@"+strings.ToLower(username))
+		}
+	}
+	return own
+}
+
+// HasLinksInBio checks if a user's bio has links, mentions, phone numbers or wallets
+// that are not their own and not allowed in the group
+func HasLinksInBio(bot *telego.Bot, group *models.GroupInfo, user telego.User) bool {
+	profile, err := GetUserProfile(bot, user.ID)
+	if err != nil {
+		logger.Warningf("Error getting chat info for user %d: %v", user.ID, err)
+		return false
+	}
+
+	suspicious, denied := SuspiciousBioEntities(group, profile.Bio, OwnUsernames(user, profile))
+	return len(suspicious) > 0 || len(denied) > 0
+}
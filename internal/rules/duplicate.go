@@ -0,0 +1,258 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+)
+
+// DefaultDuplicateWindow is how long messages stay in the fingerprint index unless configured otherwise
+const DefaultDuplicateWindow = 10 * time.Minute
+
+// minDuplicateTextLength is the number of runes from which a text is fingerprinted, short greetings repeat naturally
+const minDuplicateTextLength = 20
+
+// duplicateSighting is a message seen with a fingerprint
+type duplicateSighting struct {
+	chatID    int64
+	user      telego.User
+	messageID int
+	at        time.Time
+	purged    bool
+}
+
+// duplicateIndex keeps the recent messages of all groups by fingerprint
+type duplicateIndex struct {
+	mu        sync.Mutex
+	window    time.Duration
+	sightings map[string][]*duplicateSighting
+	lastSweep time.Time
+}
+
+var duplicates = &duplicateIndex{window: DefaultDuplicateWindow, sightings: make(map[string][]*duplicateSighting)}
+
+func init() {
+	Register(duplicateRule{})
+}
+
+// SetDuplicateWindow sets how long messages stay in the fingerprint index shared by all groups
+func SetDuplicateWindow(window time.Duration) {
+	if window <= 0 {
+		window = DefaultDuplicateWindow
+	}
+	duplicates.mu.Lock()
+	duplicates.window = window
+	duplicates.mu.Unlock()
+}
+
+// MessageFingerprints returns the fingerprints of a message: the hash of its normalized text
+// and the file_unique_id of its media. Stickers are left out, popular ones are shared everywhere.
+func MessageFingerprints(message *telego.Message) []string {
+	if message == nil {
+		return nil
+	}
+
+	var fingerprints []string
+	text := strings.Join(strings.Fields(strings.ToLower(normalize.Skeleton(message.Text+" "+message.Caption))), " ")
+	if len([]rune(text)) >= minDuplicateTextLength {
+		sum := sha256.Sum256([]byte(text))
+		fingerprints = append(fingerprints, "text:"+hex.EncodeToString(sum[:16]))
+	}
+
+	var media string
+	switch {
+	case len(message.Photo) > 0:
+		media = message.Photo[len(message.Photo)-1].FileUniqueID
+	case message.Video != nil:
+		media = message.Video.FileUniqueID
+	case message.Animation != nil:
+		media = message.Animation.FileUniqueID
+	case message.Document != nil:
+		media = message.Document.FileUniqueID
+	case message.Audio != nil:
+		media = message.Audio.FileUniqueID
+	case message.Voice != nil:
+		media = message.Voice.FileUniqueID
+	case message.VideoNote != nil:
+		media = message.VideoNote.FileUniqueID
+	}
+	if media != "" {
+		fingerprints = append(fingerprints, "media:"+media)
+	}
+	return fingerprints
+}
+
+// record adds the message to the index and returns the most distinct groups and users
+// that posted any of its fingerprints within the window, including this message
+func (d *duplicateIndex) record(fingerprints []string, sighting *duplicateSighting) (groups, users int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if sighting.at.Sub(d.lastSweep) >= d.window {
+		for fingerprint := range d.sightings {
+			d.prune(fingerprint, sighting.at)
+		}
+		d.lastSweep = sighting.at
+	}
+
+	for _, fingerprint := range fingerprints {
+		d.prune(fingerprint, sighting.at)
+		d.sightings[fingerprint] = append(d.sightings[fingerprint], sighting)
+
+		chats := make(map[int64]bool)
+		senders := make(map[int64]bool)
+		for _, s := range d.sightings[fingerprint] {
+			chats[s.chatID] = true
+			senders[s.user.ID] = true
+		}
+		groups, users = max(groups, len(chats)), max(users, len(senders))
+	}
+	return groups, users
+}
+
+// prune drops sightings of a fingerprint that left the window
+func (d *duplicateIndex) prune(fingerprint string, now time.Time) {
+	sightings := d.sightings[fingerprint]
+	start := 0
+	for start < len(sightings) && now.Sub(sightings[start].at) >= d.window {
+		start++
+	}
+	if start == len(sightings) {
+		delete(d.sightings, fingerprint)
+	} else if start > 0 {
+		d.sightings[fingerprint] = sightings[start:]
+	}
+}
+
+// DuplicateCopy is a message in any group that shares a fingerprint with a message flagged as duplicate spam,
+// with the most distinct groups and users that posted any of the fingerprints they share
+type DuplicateCopy struct {
+	ChatID    int64
+	User      telego.User
+	MessageID int
+	Groups    int
+	Users     int
+
+	sighting *duplicateSighting
+}
+
+// DuplicateCopies returns the other copies of a message flagged as duplicate spam that were not claimed yet.
+// The flagged message itself is claimed, it is removed by whoever flagged it.
+func DuplicateCopies(message *telego.Message) []DuplicateCopy {
+	duplicates.mu.Lock()
+	defer duplicates.mu.Unlock()
+
+	now := time.Now()
+	var copies []DuplicateCopy
+	index := make(map[*duplicateSighting]int)
+	for _, fingerprint := range MessageFingerprints(message) {
+		duplicates.prune(fingerprint, now)
+		sightings := duplicates.sightings[fingerprint]
+
+		chats := make(map[int64]bool)
+		senders := make(map[int64]bool)
+		for _, s := range sightings {
+			chats[s.chatID] = true
+			senders[s.user.ID] = true
+		}
+
+		for _, s := range sightings {
+			if s.chatID == message.Chat.ID && s.messageID == message.MessageID {
+				s.purged = true
+				continue
+			}
+			if s.purged {
+				continue
+			}
+			if i, ok := index[s]; ok {
+				copies[i].Groups, copies[i].Users = max(copies[i].Groups, len(chats)), max(copies[i].Users, len(senders))
+				continue
+			}
+			index[s] = len(copies)
+			copies = append(copies, DuplicateCopy{ChatID: s.chatID, User: s.user, MessageID: s.messageID,
+				Groups: len(chats), Users: len(senders), sighting: s})
+		}
+	}
+	return copies
+}
+
+// ClaimDuplicateCopy marks a copy as removed and reports whether it was still unclaimed,
+// so every copy is removed once however many of its campaign's messages cross a limit
+func ClaimDuplicateCopy(c DuplicateCopy) bool {
+	duplicates.mu.Lock()
+	defer duplicates.mu.Unlock()
+
+	if c.sighting == nil || c.sighting.purged {
+		return false
+	}
+	c.sighting.purged = true
+	return true
+}
+
+// DuplicateEnabled reports whether any of the group's duplicate limits is set
+func DuplicateEnabled(group *models.GroupInfo) bool {
+	return group.DuplicateGroups > 0 || group.DuplicateUsers > 0
+}
+
+// DuplicateExceeded reports whether a message posted in groups distinct groups by users distinct users
+// exceeds any of the group's duplicate limits
+func DuplicateExceeded(group *models.GroupInfo, groups, users int) bool {
+	return (group.DuplicateGroups > 0 && groups > group.DuplicateGroups) || (group.DuplicateUsers > 0 && users > group.DuplicateUsers)
+}
+
+// RecordDuplicates adds the subject's message to the fingerprint index shared by all groups, once,
+// and returns the most distinct groups and users that posted any of its fingerprints within the window.
+// Every group the bot administers records its messages, the limits only decide where the rule acts.
+func (s *Subject) RecordDuplicates() (groups, users int) {
+	if s.duplicatesRecorded {
+		return s.duplicateGroups, s.duplicateUsers
+	}
+	s.duplicatesRecorded = true
+	if s.Message == nil || s.Group == nil {
+		return 0, 0
+	}
+	fingerprints := MessageFingerprints(s.Message)
+	if len(fingerprints) == 0 {
+		return 0, 0
+	}
+	s.duplicateGroups, s.duplicateUsers = duplicates.record(fingerprints, &duplicateSighting{
+		chatID:    s.Group.GroupID,
+		user:      s.User,
+		messageID: s.Message.MessageID,
+		at:        time.Now(),
+	})
+	return s.duplicateGroups, s.duplicateUsers
+}
+
+// DuplicateRuleID identifies the cross-group duplicate detector
+const DuplicateRuleID = "duplicate"
+
+// duplicateRule restricts senders of a message that was posted in more distinct groups
+// or by more distinct users than the group allows within the window
+type duplicateRule struct{}
+
+func (duplicateRule) ID() string                           { return DuplicateRuleID }
+func (duplicateRule) Order() int                           { return 7 }
+func (duplicateRule) Events() Event                        { return EventMessage }
+func (duplicateRule) Enabled(group *models.GroupInfo) bool { return DuplicateEnabled(group) }
+
+func (r duplicateRule) Evaluate(subject *Subject) Result {
+	groups, users := subject.RecordDuplicates()
+	if !DuplicateExceeded(subject.Group, groups, users) {
+		return pass(r.ID())
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  VerdictRestrict,
+		Reason:   "reason_duplicate",
+		Evidence: fmt.Sprintf("posted in %d groups by %d users", groups, users),
+	}
+}
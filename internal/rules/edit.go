@@ -0,0 +1,42 @@
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	"tg-antispam/internal/models"
+)
+
+func init() {
+	Register(editWindowRule{})
+}
+
+// EditAge returns how long after posting the message was edited
+func EditAge(subject *Subject) time.Duration {
+	if subject.Message == nil || subject.Message.EditDate == 0 {
+		return 0
+	}
+	return time.Unix(subject.Message.EditDate, 0).Sub(time.Unix(subject.Message.Date, 0))
+}
+
+// editWindowRule deletes messages edited later than the group's edit window allows,
+// harmless messages edited into advertisements long after posting are a common trick
+type editWindowRule struct{}
+
+func (editWindowRule) ID() string                           { return "edit_window" }
+func (editWindowRule) Order() int                           { return 8 }
+func (editWindowRule) Events() Event                        { return EventEdit }
+func (editWindowRule) Enabled(group *models.GroupInfo) bool { return group.EditWindowMinutes > 0 }
+
+func (r editWindowRule) Evaluate(subject *Subject) Result {
+	age := EditAge(subject)
+	if age <= time.Duration(subject.Group.EditWindowMinutes)*time.Minute {
+		return pass(r.ID())
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  VerdictDelete,
+		Reason:   "reason_late_edit",
+		Evidence: fmt.Sprintf("edited %s after posting", age.Round(time.Second)),
+	}
+}
@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"unicode"
+
+	"tg-antispam/internal/models"
+)
+
+// EmojiStats counts emoji grapheme clusters and letters in s.
+// A flag, keycap, skin-toned or ZWJ joined sequence counts as a single emoji.
+func EmojiStats(s string) (emoji int, letters int) {
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case isRegionalIndicator(r):
+			// two regional indicators form one flag
+			emoji++
+			if i+1 < len(runes) && isRegionalIndicator(runes[i+1]) {
+				i++
+			}
+		case isKeycapBase(r) && isKeycap(runes[i+1:]):
+			emoji++
+			i = skipEmojiExtenders(runes, i+1) - 1
+		case isEmojiStart(runes, i):
+			emoji++
+			i = skipEmojiExtenders(runes, i+1) - 1
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	return emoji, letters
+}
+
+// HasEmoji checks if a string contains emoji
+func HasEmoji(s string) bool {
+	emoji, _ := EmojiStats(s)
+	return emoji > 0
+}
+
+// ExceedsEmojiPolicy reports whether s has enough emoji to trigger the group's emoji name check:
+// at least EmojiMinCount emoji, or an emoji-to-letter ratio of at least EmojiRatio percent.
+func ExceedsEmojiPolicy(group *models.GroupInfo, s string) bool {
+	emoji, letters := EmojiStats(s)
+	if emoji == 0 {
+		return false
+	}
+	if emoji >= max(group.EmojiMinCount, 1) {
+		return true
+	}
+	return group.EmojiRatio > 0 && emoji*100 >= group.EmojiRatio*letters
+}
+
+// isPictographic reports whether r is Extended_Pictographic
+func isPictographic(r rune) bool {
+	return unicode.Is(extendedPictographic, r)
+}
+
+// isEmojiStart reports whether runes[i] starts an emoji: a pictograph that renders as emoji by default,
+// or a text-style one such as ©, ™ or an arrow that a variation selector turns into emoji
+func isEmojiStart(runes []rune, i int) bool {
+	r := runes[i]
+	if !isPictographic(r) {
+		return false
+	}
+	if r >= 0x1F000 || unicode.Is(emojiPresentation, r) {
+		return true
+	}
+	return i+1 < len(runes) && runes[i+1] == 0xFE0F
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isKeycapBase(r rune) bool {
+	return (r >= '0' && r <= '9') || r == '#' || r == '*'
+}
+
+// isKeycap reports whether the runes following a keycap base complete a keycap, e.g. "1️⃣"
+func isKeycap(rest []rune) bool {
+	if len(rest) > 0 && rest[0] == 0xFE0F {
+		rest = rest[1:]
+	}
+	return len(rest) > 0 && rest[0] == 0x20E3
+}
+
+// skipEmojiExtenders returns the index after the variation selectors, skin tone modifiers,
+// tags, keycap marks and ZWJ joined pictographs that belong to the emoji before i
+func skipEmojiExtenders(runes []rune, i int) int {
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case r == 0xFE0E || r == 0xFE0F, // variation selectors
+			r >= 0x1F3FB && r <= 0x1F3FF, // skin tone modifiers
+			r >= 0xE0020 && r <= 0xE007F, // tag sequences, e.g. subdivision flags
+			r == 0x20E3:                  // combining enclosing keycap
+			i++
+		case r == 0x200D && i+1 < len(runes) && (isPictographic(runes[i+1]) || isRegionalIndicator(runes[i+1])):
+			i += 2
+		default:
+			return i
+		}
+	}
+	return i
+}
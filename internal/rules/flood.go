@@ -0,0 +1,186 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/models"
+)
+
+// Actions applied to users exceeding the group's flood limits
+const (
+	FloodActionDelete   = "delete"
+	FloodActionMute     = "mute"
+	FloodActionRestrict = "restrict"
+)
+
+// Defaults used when a group has no window or mute duration set
+const (
+	DefaultFloodWindowSec   = 10
+	DefaultFloodMuteMinutes = 10
+)
+
+// MaxFloodWindowSec bounds the sliding window, windows of users who stopped sending are dropped after it
+const MaxFloodWindowSec = 300
+
+// floodEvent is a single message in a user's window
+type floodEvent struct {
+	at       time.Time
+	media    bool
+	mentions int
+}
+
+type floodKey struct {
+	chatID int64
+	userID int64
+}
+
+// floodTracker keeps the recent messages of every user by chat
+type floodTracker struct {
+	mu        sync.Mutex
+	windows   map[floodKey][]floodEvent
+	lastSweep time.Time
+}
+
+var flood = &floodTracker{windows: make(map[floodKey][]floodEvent)}
+
+func init() {
+	Register(floodRule{})
+}
+
+// FloodCounts is what a user sent in a group within the window
+type FloodCounts struct {
+	Messages int
+	Media    int
+	Mentions int
+}
+
+// record adds the message to the user's window and returns the counts within the window, including it
+func (t *floodTracker) record(key floodKey, event floodEvent, window time.Duration) FloodCounts {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if event.at.Sub(t.lastSweep) >= MaxFloodWindowSec*time.Second {
+		for k, events := range t.windows {
+			if len(events) == 0 || event.at.Sub(events[len(events)-1].at) >= MaxFloodWindowSec*time.Second {
+				delete(t.windows, k)
+			}
+		}
+		t.lastSweep = event.at
+	}
+
+	events := append(t.windows[key], event)
+	start := 0
+	for start < len(events) && event.at.Sub(events[start].at) >= window {
+		start++
+	}
+	events = events[start:]
+	t.windows[key] = events
+
+	var counts FloodCounts
+	for _, e := range events {
+		counts.Messages++
+		if e.media {
+			counts.Media++
+		}
+		counts.Mentions += e.mentions
+	}
+	return counts
+}
+
+// HasMedia reports whether a message carries a photo, video, animation, sticker, document or voice
+func HasMedia(message *telego.Message) bool {
+	return len(message.Photo) > 0 || message.Video != nil || message.Animation != nil || message.Sticker != nil ||
+		message.Document != nil || message.Audio != nil || message.Voice != nil || message.VideoNote != nil
+}
+
+// CountMentions counts the @username and text mentions in a message's text and caption
+func CountMentions(message *telego.Message) int {
+	count := 0
+	for _, entities := range [][]telego.MessageEntity{message.Entities, message.CaptionEntities} {
+		for _, entity := range entities {
+			if entity.Type == "mention" || entity.Type == "text_mention" {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// FloodEnabled reports whether any of the group's flood limits is set
+func FloodEnabled(group *models.GroupInfo) bool {
+	return group.FloodMessages > 0 || group.FloodMedia > 0 || group.FloodMentions > 0
+}
+
+// FloodWindow returns the group's sliding window
+func FloodWindow(group *models.GroupInfo) time.Duration {
+	if group.FloodWindowSec <= 0 {
+		return DefaultFloodWindowSec * time.Second
+	}
+	return time.Duration(min(group.FloodWindowSec, MaxFloodWindowSec)) * time.Second
+}
+
+// FloodMuteDuration returns how long users exceeding the group's flood limits are muted
+func FloodMuteDuration(group *models.GroupInfo) time.Duration {
+	if group.FloodMuteMinutes <= 0 {
+		return DefaultFloodMuteMinutes * time.Minute
+	}
+	return time.Duration(group.FloodMuteMinutes) * time.Minute
+}
+
+// FloodVerdict returns the verdict for a user exceeding the group's flood limits
+func FloodVerdict(group *models.GroupInfo) Verdict {
+	switch group.FloodAction {
+	case FloodActionDelete:
+		return VerdictDelete
+	case FloodActionRestrict:
+		return VerdictRestrict
+	}
+	return VerdictMute
+}
+
+// floodRule counts messages, media and mentions of each user in a sliding window
+// and acts on users exceeding any of the group's limits
+type floodRule struct{}
+
+func (floodRule) ID() string                           { return "flood" }
+func (floodRule) Order() int                           { return 5 }
+func (floodRule) Events() Event                        { return EventMessage }
+func (floodRule) Enabled(group *models.GroupInfo) bool { return FloodEnabled(group) }
+
+func (r floodRule) Evaluate(subject *Subject) Result {
+	if subject.Message == nil {
+		return pass(r.ID())
+	}
+	group := subject.Group
+	window := FloodWindow(group)
+	counts := flood.record(floodKey{chatID: group.GroupID, userID: subject.User.ID}, floodEvent{
+		at:       time.Now(),
+		media:    HasMedia(subject.Message),
+		mentions: CountMentions(subject.Message),
+	}, window)
+
+	var exceeded []string
+	if group.FloodMessages > 0 && counts.Messages > group.FloodMessages {
+		exceeded = append(exceeded, fmt.Sprintf("messages=%d/%d", counts.Messages, group.FloodMessages))
+	}
+	if group.FloodMedia > 0 && counts.Media > group.FloodMedia {
+		exceeded = append(exceeded, fmt.Sprintf("media=%d/%d", counts.Media, group.FloodMedia))
+	}
+	if group.FloodMentions > 0 && counts.Mentions > group.FloodMentions {
+		exceeded = append(exceeded, fmt.Sprintf("mentions=%d/%d", counts.Mentions, group.FloodMentions))
+	}
+	if len(exceeded) == 0 {
+		return pass(r.ID())
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  FloodVerdict(group),
+		Reason:   "reason_flood",
+		Evidence: fmt.Sprintf("%s in %s", strings.Join(exceeded, ", "), window),
+	}
+}
@@ -0,0 +1,108 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// Policies for messages forwarded from channels
+const (
+	ForwardChannelsAllow     = "allow"
+	ForwardChannelsBlock     = "block"
+	ForwardChannelsAllowlist = "allowlist"
+)
+
+// ForwardChannelPolicies is the order the channel policy cycles through in the settings
+var ForwardChannelPolicies = []string{ForwardChannelsAllow, ForwardChannelsBlock, ForwardChannelsAllowlist}
+
+func init() {
+	Register(forwardRule{})
+}
+
+// ForwardChannelPolicy returns the group's channel forward policy, unknown values allow forwards
+func ForwardChannelPolicy(group *models.GroupInfo) string {
+	switch group.ForwardChannelPolicy {
+	case ForwardChannelsBlock, ForwardChannelsAllowlist:
+		return group.ForwardChannelPolicy
+	}
+	return ForwardChannelsAllow
+}
+
+// NormalizeForwardListValue validates a channel ID for the forward allow list, e.g. "-1001234567890"
+func NormalizeForwardListValue(value string) (string, bool) {
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id >= 0 {
+		return "", false
+	}
+	return strconv.FormatInt(id, 10), true
+}
+
+// forwardRule applies the group's forwarding policies: channel forwards may be blocked
+// or limited to listed channels, and forwards from users hiding their account may be blocked.
+// Automatic forwards and posts of the group's linked channel are always allowed
+type forwardRule struct{}
+
+func (forwardRule) ID() string    { return "forward_origin" }
+func (forwardRule) Order() int    { return 6 }
+func (forwardRule) Events() Event { return EventMessage }
+
+func (forwardRule) Enabled(group *models.GroupInfo) bool {
+	return ForwardChannelPolicy(group) != ForwardChannelsAllow || group.BlockHiddenForwards
+}
+
+func (r forwardRule) Evaluate(subject *Subject) Result {
+	if subject.Message == nil || subject.Message.IsAutomaticForward {
+		return pass(r.ID())
+	}
+	group := subject.Group
+	// Posts the linked channel makes in its discussion group are not forwards by members
+	if sender := subject.Message.SenderChat; sender != nil {
+		linked, err := service.GetLinkedChatID(subject.Bot, group.GroupID)
+		if err != nil {
+			logger.Warningf("Error getting linked chat of chat %d: %v", group.GroupID, err)
+		} else if linked != 0 && sender.ID == linked {
+			return pass(r.ID())
+		}
+	}
+	switch origin := subject.Message.ForwardOrigin.(type) {
+	case *telego.MessageOriginChannel:
+		channel := strconv.FormatInt(origin.Chat.ID, 10)
+		switch ForwardChannelPolicy(group) {
+		case ForwardChannelsBlock:
+		case ForwardChannelsAllowlist:
+			if containsString(service.GetGroupList(group.GroupID, models.GroupListForwardAllow), channel) {
+				return pass(r.ID())
+			}
+		default:
+			return pass(r.ID())
+		}
+		evidence := fmt.Sprintf("channel %s", channel)
+		if origin.Chat.Username != "" {
+			evidence += " @" + origin.Chat.Username
+		}
+		return Result{
+			RuleID:   r.ID(),
+			Verdict:  ActionVerdict(group.ForwardChannelAction),
+			Reason:   "reason_forward_channel",
+			Evidence: evidence,
+		}
+	case *telego.MessageOriginHiddenUser:
+		if !group.BlockHiddenForwards {
+			return pass(r.ID())
+		}
+		return Result{
+			RuleID:   r.ID(),
+			Verdict:  ActionVerdict(group.HiddenForwardAction),
+			Reason:   "reason_forward_hidden",
+			Evidence: fmt.Sprintf("hidden user %q", origin.SenderUserName),
+		}
+	}
+	return pass(r.ID())
+}
@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+	"tg-antispam/internal/service"
+)
+
+// ImpersonationRuleID is the ID of the admin impersonation rule
+const ImpersonationRuleID = "impersonation"
+
+// impersonationMinLength is the shortest folded name that is compared, shorter names collide too easily
+const impersonationMinLength = 4
+
+// impersonationStrongLength is the shortest folded name whose match alone restricts, shorter
+// matches like "David" or a two character Chinese name only add ScoreImpersonation
+const impersonationStrongLength = 8
+
+// impersonationDecorations are words scammers add to a copied name to look official, longest first
+var impersonationDecorations = []string{
+	"administrator", "moderator", "helpdesk", "official", "support", "admin", "owner",
+	"管理员", "管理員", "管理", "客服", "官方", "群主",
+}
+
+// leetReplacer folds digits and symbols commonly used in place of letters
+var leetReplacer = strings.NewReplacer("0", "o", "1", "l", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+func init() {
+	Register(impersonationRule{})
+}
+
+// foldIdentity reduces a name or username to lowercase letters and digits with
+// confusables, leetspeak and official-looking decorations removed
+func foldIdentity(s string) string {
+	s = strings.ToLower(normalize.Skeleton(s))
+	s = leetReplacer.Replace(s)
+	for _, decoration := range impersonationDecorations {
+		s = strings.ReplaceAll(s, decoration, "")
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// identityLength weighs Han characters double, a two character Chinese name carries as much as a short Latin one
+func identityLength(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// identities returns the folded full name and username of a user, empty when too short to compare
+func identities(user telego.User) [2]string {
+	var ids [2]string
+	for i, s := range []string{user.FirstName + " " + user.LastName, user.Username} {
+		if folded := foldIdentity(s); identityLength(folded) >= impersonationMinLength {
+			ids[i] = folded
+		}
+	}
+	return ids
+}
+
+// impersonationDistance is the edit distance up to which a name counts as a copy of an admin's name
+func impersonationDistance(name string) int {
+	switch n := identityLength(name); {
+	case n >= 10:
+		return 2
+	case n >= 6:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// ImpersonationMatch describes a user imitating an administrator
+type ImpersonationMatch struct {
+	Admin    telego.User
+	Evidence string
+	// Strong is set when the copied name is long enough or both name and username match,
+	// a weak match is common enough to happen by chance
+	Strong bool
+}
+
+// MatchImpersonation returns the administrator whose name or username the user imitates, if any
+func MatchImpersonation(admins []telego.ChatMember, user telego.User) (*ImpersonationMatch, bool) {
+	userIDs := identities(user)
+	if userIDs[0] == "" && userIDs[1] == "" {
+		return nil, false
+	}
+
+	var weak *ImpersonationMatch
+	for _, admin := range admins {
+		adminUser := admin.MemberUser()
+		if adminUser.ID == user.ID {
+			return nil, false
+		}
+
+		var evidence []string
+		var matched [2]bool
+		long := false
+		for _, adminID := range identities(adminUser) {
+			if adminID == "" {
+				continue
+			}
+			for i, userID := range userIDs {
+				if userID == "" || matched[i] {
+					continue
+				}
+				if distance := levenshtein(userID, adminID); distance <= impersonationDistance(adminID) {
+					matched[i] = true
+					long = long || identityLength(adminID) >= impersonationStrongLength
+					evidence = append(evidence, fmt.Sprintf("%q ~ %q (distance %d)", userID, adminID, distance))
+				}
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		match := &ImpersonationMatch{
+			Admin:    adminUser,
+			Evidence: strings.Join(evidence, ", "),
+			Strong:   long || (matched[0] && matched[1]),
+		}
+		if match.Strong {
+			return match, true
+		}
+		if weak == nil {
+			weak = match
+		}
+	}
+	return weak, weak != nil
+}
+
+// impersonationRule restricts users whose name or username clearly imitates an admin of the group,
+// and scores weaker matches so they need another signal
+type impersonationRule struct{}
+
+func (impersonationRule) ID() string                           { return ImpersonationRuleID }
+func (impersonationRule) Order() int                           { return 8 }
+func (impersonationRule) Events() Event                        { return EventJoin }
+func (impersonationRule) Enabled(group *models.GroupInfo) bool { return group.BanImpersonation }
+
+func (r impersonationRule) Evaluate(subject *Subject) Result {
+	admins, err := service.GetChatAdministrators(subject.Bot, subject.Group.GroupID)
+	if err != nil {
+		logger.Warningf("Error getting administrators of chat %d: %v", subject.Group.GroupID, err)
+		return pass(r.ID())
+	}
+
+	match, ok := MatchImpersonation(admins, subject.User)
+	if !ok {
+		return pass(r.ID())
+	}
+	evidence := fmt.Sprintf("admin %d: %s", match.Admin.ID, match.Evidence)
+	if !match.Strong {
+		return signal(r.ID(), ScoreImpersonation, "reason_impersonation", evidence)
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  VerdictRestrict,
+		Reason:   "reason_impersonation",
+		Evidence: evidence,
+	}
+}
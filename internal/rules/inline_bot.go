@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+)
+
+// botUsernameRegex matches a bot username, optionally as @username or t.me link.
+// Telegram's own inline bots such as @gif have short names without the "bot" suffix.
+var botUsernameRegex = regexp.MustCompile(`(?i)^(?:@|(?:https?://)?(?:t\.me|telegram\.me)/)?([a-z][a-z0-9_]{2,31})/?$`)
+
+func init() {
+	Register(inlineBotRule{})
+}
+
+// NormalizeInlineBotValue validates a bot username for the inline bot allow list and returns it as "@username"
+func NormalizeInlineBotValue(value string) (string, bool) {
+	m := botUsernameRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return "", false
+	}
+	return "@" + strings.ToLower(m[1]), true
+}
+
+// inlineBotRule deletes messages sent through inline bots that are not on the group's allow list
+type inlineBotRule struct{}
+
+func (inlineBotRule) ID() string                           { return "inline_bot" }
+func (inlineBotRule) Order() int                           { return 9 }
+func (inlineBotRule) Events() Event                        { return EventMessage | EventEdit }
+func (inlineBotRule) Enabled(group *models.GroupInfo) bool { return group.BlockInlineBots }
+
+func (r inlineBotRule) Evaluate(subject *Subject) Result {
+	if subject.Message == nil || subject.Message.ViaBot == nil {
+		return pass(r.ID())
+	}
+	bot := "@" + strings.ToLower(subject.Message.ViaBot.Username)
+	if containsString(service.GetGroupList(subject.Group.GroupID, models.GroupListInlineBotAllow), bot) {
+		return pass(r.ID())
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  VerdictDelete,
+		Reason:   "reason_inline_bot",
+		Evidence: "via " + bot,
+	}
+}
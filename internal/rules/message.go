@@ -0,0 +1,166 @@
+package rules
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/models"
+)
+
+// Actions applied to messages flagged by the message scan
+const (
+	MessageActionDelete   = "delete"
+	MessageActionRestrict = "restrict"
+)
+
+func init() {
+	Register(messageProviderRule{})
+}
+
+// MessageText returns the text a message carries: its text or caption, the quoted part of the
+// replied message, the chat or user it was forwarded from and the URLs hidden in its links and buttons, one per line
+func MessageText(message *telego.Message) string {
+	if message == nil {
+		return ""
+	}
+
+	var parts []string
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	add(message.Text)
+	add(message.Caption)
+	if message.Quote != nil {
+		add(message.Quote.Text)
+	}
+	switch origin := message.ForwardOrigin.(type) {
+	case *telego.MessageOriginUser:
+		add(strings.TrimSpace(origin.SenderUser.FirstName + " " + origin.SenderUser.LastName))
+		if origin.SenderUser.Username != "" {
+			add("@" + origin.SenderUser.Username)
+		}
+	case *telego.MessageOriginHiddenUser:
+		add(origin.SenderUserName)
+	case *telego.MessageOriginChat:
+		add(origin.SenderChat.Title)
+		if origin.SenderChat.Username != "" {
+			add("@" + origin.SenderChat.Username)
+		}
+	case *telego.MessageOriginChannel:
+		add(origin.Chat.Title)
+		if origin.Chat.Username != "" {
+			add("@" + origin.Chat.Username)
+		}
+	}
+	for _, url := range MessageURLs(message) {
+		if !strings.Contains(message.Text+message.Caption, url) {
+			add(url)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// MessageURLs returns the URLs of a message's url and text_link entities and of its inline keyboard buttons.
+// Each distinct URL is reported once.
+func MessageURLs(message *telego.Message) []string {
+	if message == nil {
+		return nil
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if url = strings.TrimSpace(url); url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	for _, source := range []struct {
+		text     string
+		entities []telego.MessageEntity
+	}{
+		{message.Text, message.Entities},
+		{message.Caption, message.CaptionEntities},
+	} {
+		for _, entity := range source.entities {
+			switch entity.Type {
+			case "text_link":
+				add(entity.URL)
+			case "url":
+				add(entityText(source.text, entity))
+			}
+		}
+	}
+	if message.ReplyMarkup != nil {
+		for _, row := range message.ReplyMarkup.InlineKeyboard {
+			for _, button := range row {
+				add(button.URL)
+				if button.LoginURL != nil {
+					add(button.LoginURL.URL)
+				}
+			}
+		}
+	}
+	return urls
+}
+
+// entityText returns the part of text an entity covers, entity offsets count UTF-16 code units
+func entityText(text string, entity telego.MessageEntity) string {
+	units := utf16.Encode([]rune(text))
+	if entity.Offset < 0 || entity.Length <= 0 || entity.Offset+entity.Length > len(units) {
+		return ""
+	}
+	return string(utf16.Decode(units[entity.Offset : entity.Offset+entity.Length]))
+}
+
+// IsSuspiciousText reports whether text contains links, mentions, phone numbers or wallet addresses.
+// Only such messages are passed to the costly detectors of the message scan.
+func IsSuspiciousText(text string) bool {
+	return len(AnalyzeBio(text)) > 0
+}
+
+// MessageVerdict returns the verdict for a message flagged by the message scan:
+// delete only, or delete and restrict the sender
+func MessageVerdict(group *models.GroupInfo) Verdict {
+	return ActionVerdict(group.MessageAction)
+}
+
+// ActionVerdict maps a message action to a verdict, anything but delete also restricts the sender
+func ActionVerdict(action string) Verdict {
+	if action == MessageActionDelete {
+		return VerdictDelete
+	}
+	return VerdictRestrict
+}
+
+// messageProviderRule checks senders of suspicious messages against the group's blocklist providers
+type messageProviderRule struct{}
+
+func (messageProviderRule) ID() string    { return "message_blocklist_provider" }
+func (messageProviderRule) Order() int    { return 55 }
+func (messageProviderRule) Events() Event { return EventMessage | EventEdit }
+
+func (messageProviderRule) Enabled(group *models.GroupInfo) bool {
+	return group.EnableMessageScan && group.MessageProviderCheck && (group.EnableCAS || group.BlocklistProviders != "")
+}
+
+func (r messageProviderRule) Evaluate(subject *Subject) Result {
+	if !IsSuspiciousText(subject.Text()) {
+		return pass(r.ID())
+	}
+	hits, reason := listedByProviders(subject.Group, subject.User.ID)
+	if len(hits) == 0 {
+		return pass(r.ID())
+	}
+	return Result{
+		RuleID:   r.ID(),
+		Verdict:  MessageVerdict(subject.Group),
+		Reason:   reason,
+		Evidence: strings.Join(hits, ", "),
+	}
+}
@@ -0,0 +1,28 @@
+package rules
+
+// Reasons of the classifiers. A classifier never learns from its own verdicts, they would only reinforce themselves.
+const (
+	ReasonBayesSpam = "reason_bayes_spam"
+	ReasonAISpam    = "reason_ai_spam"
+)
+
+// nonContentReasons are the reasons of rules that judge how, where or when a message was sent rather than what it says
+var nonContentReasons = []string{
+	"reason_join_group",
+	"reason_flood",
+	"reason_late_edit",
+	"reason_inline_bot",
+	"reason_forward_channel",
+	"reason_forward_hidden",
+}
+
+// ContentReason reports whether reason judges the content of a message, so that the message may
+// serve the classifiers as a spam sample. Each classifier also leaves out its own reason.
+func ContentReason(reason string) bool {
+	return !containsString(nonContentReasons, reason)
+}
+
+// NonContentReasons returns the reasons for which ContentReason is false
+func NonContentReasons() []string {
+	return append([]string(nil), nonContentReasons...)
+}
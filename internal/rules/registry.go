@@ -0,0 +1,211 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"tg-antispam/internal/logger"
+)
+
+// Registry keeps the registered rules sorted by order
+type Registry struct {
+	rules          []Rule
+	scoreOverrides map[string]int
+	mu             sync.RWMutex
+}
+
+// Decision is the combined outcome of evaluating all applicable rules
+type Decision struct {
+	Verdict  Verdict
+	Score    int
+	Reason   string
+	Evidence string
+	Event    Event    // the event the rules were evaluated for
+	Results  []Result // all matched results, in evaluation order
+}
+
+// NewDecision creates a decision that is not backed by any rule result
+func NewDecision(verdict Verdict, reason string) Decision {
+	return Decision{Verdict: verdict, Reason: reason}
+}
+
+// Breakdown returns the per-rule score contributions, e.g. "premium_user+30, emoji_name+30"
+func (d Decision) Breakdown() string {
+	var parts []string
+	for _, result := range d.Results {
+		if result.Score > 0 {
+			parts = append(parts, fmt.Sprintf("%s+%d", result.RuleID, result.Score))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Result returns the matched result of the rule with the given ID
+func (d Decision) Result(ruleID string) (Result, bool) {
+	for _, result := range d.Results {
+		if result.RuleID == ruleID {
+			return result, true
+		}
+	}
+	return Result{}, false
+}
+
+// NewRegistry creates an empty rule registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a rule to the registry, replacing any rule with the same ID
+func (r *Registry) Register(rule Rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.rules {
+		if existing.ID() == rule.ID() {
+			r.rules[i] = rule
+			r.sort()
+			return
+		}
+	}
+	r.rules = append(r.rules, rule)
+	r.sort()
+}
+
+// Unregister removes the rule with the given ID
+func (r *Registry) Unregister(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.rules {
+		if existing.ID() == id {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetScoreOverrides replaces the default score of rules by rule ID
+func (r *Registry) SetScoreOverrides(overrides map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scoreOverrides = make(map[string]int, len(overrides))
+	for id, score := range overrides {
+		r.scoreOverrides[id] = score
+	}
+}
+
+func (r *Registry) scoreOverride(id string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	score, ok := r.scoreOverrides[id]
+	return score, ok
+}
+
+// Rules returns a snapshot of the registered rules in evaluation order
+func (r *Registry) Rules() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]Rule, len(r.rules))
+	copy(rules, r.rules)
+	return rules
+}
+
+func (r *Registry) sort() {
+	sort.SliceStable(r.rules, func(i, j int) bool {
+		return r.rules[i].Order() < r.rules[j].Order()
+	})
+}
+
+// Evaluate runs every rule enabled for the subject's group and event.
+// Scores of all matched rules are summed and mapped to the group's action bands;
+// a hard verdict from a rule wins if it is stronger than the score band.
+func (r *Registry) Evaluate(subject *Subject) Decision {
+	return r.evaluate(subject, func(Rule) bool { return true })
+}
+
+// EvaluateRule runs only the rule with the given ID, if it is enabled for the subject's group and event
+func (r *Registry) EvaluateRule(id string, subject *Subject) Decision {
+	return r.evaluate(subject, func(rule Rule) bool { return rule.ID() == id })
+}
+
+func (r *Registry) evaluate(subject *Subject, include func(Rule) bool) Decision {
+	decision := Decision{Verdict: VerdictPass}
+	if subject == nil || subject.Group == nil {
+		return decision
+	}
+	decision.Event = subject.Event
+
+	hard, top := -1, -1
+	for _, rule := range r.Rules() {
+		if !include(rule) || rule.Events()&subject.Event == 0 || !rule.Enabled(subject.Group) {
+			continue
+		}
+		if hard >= 0 && decision.Results[hard].Verdict >= VerdictDelete && isCostly(rule) {
+			logger.Debugf("Skipping rule %s for user %d in chat %d, %s already decided %s",
+				rule.ID(), subject.User.ID, subject.Group.GroupID, decision.Results[hard].RuleID, decision.Results[hard].Verdict)
+			continue
+		}
+
+		result := rule.Evaluate(subject)
+		if result.RuleID == "" {
+			result.RuleID = rule.ID()
+		}
+		if override, ok := r.scoreOverride(result.RuleID); ok && result.Score > 0 {
+			result.Score = override
+		}
+		if !result.Matched() {
+			continue
+		}
+
+		logger.Debugf("Rule %s matched user %d in chat %d: verdict=%s, score=%d, evidence=%s",
+			result.RuleID, subject.User.ID, subject.Group.GroupID, result.Verdict, result.Score, result.Evidence)
+
+		decision.Results = append(decision.Results, result)
+		decision.Score += result.Score
+		if result.Verdict != VerdictPass && (hard < 0 || result.Verdict > decision.Results[hard].Verdict) {
+			hard = len(decision.Results) - 1
+		}
+		if result.Score > 0 && (top < 0 || result.Score > decision.Results[top].Score) {
+			top = len(decision.Results) - 1
+		}
+	}
+
+	decision.Verdict = ScoreVerdict(subject.Group, decision.Score)
+	if top >= 0 {
+		decision.Reason, decision.Evidence = decision.Results[top].Reason, decision.Results[top].Evidence
+	}
+	if hard >= 0 && decision.Results[hard].Verdict >= decision.Verdict {
+		decision.Verdict = decision.Results[hard].Verdict
+		decision.Reason, decision.Evidence = decision.Results[hard].Reason, decision.Results[hard].Evidence
+	}
+
+	return decision
+}
+
+// Default is the registry used by the bot handlers
+var Default = NewRegistry()
+
+// Register adds a rule to the default registry
+func Register(rule Rule) {
+	Default.Register(rule)
+}
+
+// SetScoreOverrides replaces rule scores in the default registry
+func SetScoreOverrides(overrides map[string]int) {
+	Default.SetScoreOverrides(overrides)
+}
+
+// Evaluate runs the default registry against the subject
+func Evaluate(subject *Subject) Decision {
+	return Default.Evaluate(subject)
+}
+
+// EvaluateRule runs a single rule of the default registry against the subject
+func EvaluateRule(id string, subject *Subject) Decision {
+	return Default.EvaluateRule(id, subject)
+}
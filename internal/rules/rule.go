@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"github.com/mymmrac/telego"
+
+	"tg-antispam/internal/models"
+)
+
+// Event identifies which flow a rule is evaluated in
+type Event int
+
+const (
+	// EventJoin is evaluated when a member joins or is approved in a group
+	EventJoin Event = 1 << iota
+	// EventMessage is evaluated for every message sent in a group
+	EventMessage
+	// EventEdit is evaluated for every message edited in a group
+	EventEdit
+)
+
+// Verdict is the action a rule suggests for a subject
+type Verdict int
+
+const (
+	// VerdictPass means the rule found nothing suspicious
+	VerdictPass Verdict = iota
+	// VerdictDelete means the message should be deleted
+	VerdictDelete
+	// VerdictMute means the message should be deleted and the user muted for the group's mute duration
+	VerdictMute
+	// VerdictChallenge means the user should be restricted until solving the self-unban challenge
+	VerdictChallenge
+	// VerdictRestrict means the user should be restricted
+	VerdictRestrict
+	// VerdictBan means the user should be banned from the group
+	VerdictBan
+)
+
+// String returns the name of the verdict for logging
+func (v Verdict) String() string {
+	switch v {
+	case VerdictDelete:
+		return "delete"
+	case VerdictMute:
+		return "mute"
+	case VerdictChallenge:
+		return "challenge"
+	case VerdictRestrict:
+		return "restrict"
+	case VerdictBan:
+		return "ban"
+	default:
+		return "pass"
+	}
+}
+
+// Subject holds everything a rule may inspect
+type Subject struct {
+	Bot     *telego.Bot
+	Group   *models.GroupInfo
+	User    telego.User
+	Message *telego.Message // nil for join events, the edited version for edit events
+	Event   Event
+
+	profile       *telego.ChatFullInfo
+	profileErr    error
+	profileLoaded bool
+
+	avatarHash   uint64
+	hasAvatar    bool
+	avatarErr    error
+	avatarLoaded bool
+
+	text       string
+	textLoaded bool
+
+	duplicateGroups    int
+	duplicateUsers     int
+	duplicatesRecorded bool
+}
+
+// Profile returns the user's full chat info, fetched once and shared by all rules
+func (s *Subject) Profile() (*telego.ChatFullInfo, error) {
+	if !s.profileLoaded {
+		s.profile, s.profileErr = GetUserProfile(s.Bot, s.User.ID)
+		s.profileLoaded = true
+	}
+	return s.profile, s.profileErr
+}
+
+// Bio returns the user's bio
+func (s *Subject) Bio() (string, error) {
+	profile, err := s.Profile()
+	if err != nil {
+		return "", err
+	}
+	return profile.Bio, nil
+}
+
+// Avatar returns the perceptual hash of the user's profile photo, fetched once and shared by all rules
+func (s *Subject) Avatar() (uint64, bool, error) {
+	if !s.avatarLoaded {
+		s.avatarHash, s.hasAvatar, s.avatarErr = FetchAvatarHash(s.Bot, s.User.ID)
+		s.avatarLoaded = true
+	}
+	return s.avatarHash, s.hasAvatar, s.avatarErr
+}
+
+// Text returns the text carried by the message, empty for join events
+func (s *Subject) Text() string {
+	if !s.textLoaded {
+		s.text = MessageText(s.Message)
+		s.textLoaded = true
+	}
+	return s.text
+}
+
+// Result is the outcome of a single rule evaluation.
+// Signal rules add to the risk score, hard rules set a verdict directly.
+type Result struct {
+	RuleID   string
+	Verdict  Verdict
+	Score    int
+	Reason   string // translation key, e.g. "reason_emoji_name"
+	Evidence string // human readable detail of what matched
+}
+
+// Matched reports whether the rule flagged the subject
+func (r Result) Matched() bool {
+	return r.Verdict != VerdictPass || r.Score > 0
+}
+
+// Rule is a single spam heuristic evaluated by the engine
+type Rule interface {
+	// ID returns the unique identifier of the rule
+	ID() string
+	// Order decides evaluation order, lower values run first
+	Order() int
+	// Events returns the events the rule applies to, as a bit mask
+	Events() Event
+	// Enabled reports whether the rule is switched on for the group
+	Enabled(group *models.GroupInfo) bool
+	// Evaluate inspects the subject and returns a verdict with evidence
+	Evaluate(subject *Subject) Result
+}
+
+// CostlyRule is implemented by rules that spend money or time per evaluation, like AI calls and
+// redirect lookups. They are skipped once an earlier rule decided to delete the message or act harder.
+type CostlyRule interface {
+	Costly() bool
+}
+
+// isCostly reports whether the rule implements CostlyRule and is costly
+func isCostly(rule Rule) bool {
+	costly, ok := rule.(CostlyRule)
+	return ok && costly.Costly()
+}
+
+// pass returns a Result that does not flag the subject
+func pass(ruleID string) Result {
+	return Result{RuleID: ruleID, Verdict: VerdictPass}
+}
+
+// signal returns a Result that adds score to the subject's risk
+func signal(ruleID string, score int, reason, evidence string) Result {
+	return Result{RuleID: ruleID, Score: score, Reason: reason, Evidence: evidence}
+}
+
+// ScoreVerdict maps a risk score to the action band configured for the group
+func ScoreVerdict(group *models.GroupInfo, score int) Verdict {
+	if score <= 0 {
+		return VerdictPass
+	}
+	if group.BanScore > 0 && score >= group.BanScore {
+		return VerdictBan
+	}
+	if score >= group.RestrictScore {
+		return VerdictRestrict
+	}
+	return VerdictPass
+}
@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/service"
+	"tg-antispam/internal/urlresolve"
+)
+
+func init() {
+	Register(urlDomainRule{})
+}
+
+// urlDomainRule follows the links of a message through their redirects and flags it when a link passes
+// through a domain on the group's deny list, unless its final destination is on the group's allow list.
+// The deny list is matched against every hop on purpose, not only the final destination: denying a shortener
+// such as bit.ly blocks all of its links, including those whose destination could not be reached.
+type urlDomainRule struct{}
+
+func (urlDomainRule) ID() string    { return "url_domain" }
+func (urlDomainRule) Order() int    { return 52 }
+func (urlDomainRule) Events() Event { return EventMessage | EventEdit }
+func (urlDomainRule) Costly() bool  { return true }
+
+func (urlDomainRule) Enabled(group *models.GroupInfo) bool {
+	return group.EnableMessageScan && urlresolve.Enabled() && len(service.GetGroupList(group.GroupID, models.GroupListDomainDeny)) > 0
+}
+
+func (r urlDomainRule) Evaluate(subject *Subject) Result {
+	links := MessageURLs(subject.Message)
+	if len(links) == 0 {
+		return pass(r.ID())
+	}
+	if len(links) > urlresolve.MaxURLs() {
+		links = links[:urlresolve.MaxURLs()]
+	}
+	deny := service.GetGroupList(subject.Group.GroupID, models.GroupListDomainDeny)
+	allow := service.GetGroupList(subject.Group.GroupID, models.GroupListDomainAllow)
+
+	for _, link := range links {
+		if urlresolve.MatchAnyDomain(urlresolve.Host(link), allow) != "" {
+			continue
+		}
+		resolution, err := urlresolve.Resolve(context.Background(), link)
+		if err != nil {
+			logger.Debugf("Error resolving %s in chat %d: %v", link, subject.Group.GroupID, err)
+		}
+		if urlresolve.MatchAnyDomain(urlresolve.Host(resolution.Final()), allow) != "" {
+			continue
+		}
+		for _, host := range resolution.Hosts() {
+			if domain := urlresolve.MatchAnyDomain(host, deny); domain != "" {
+				return Result{
+					RuleID:   r.ID(),
+					Verdict:  MessageVerdict(subject.Group),
+					Reason:   "reason_url_domain",
+					Evidence: fmt.Sprintf("%s (%s)", strings.Join(resolution.Chain, " -> "), domain),
+				}
+			}
+		}
+	}
+	return pass(r.ID())
+}
@@ -0,0 +1,143 @@
+package rules
+
+import (
+	"bufio"
+	_ "embed"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+	"tg-antispam/internal/normalize"
+)
+
+// usernameModelData holds character trigram counts of English words, names and pinyin,
+// followed by the logistic regression weights that turn the features into a probability
+//
+//go:embed data/username_ngrams.txt
+var usernameModelData string
+
+var (
+	letterSegmentRegex  = regexp.MustCompile(`[a-z]+`)
+	trailingDigitsRegex = regexp.MustCompile(`\d+$`)
+	letterDigitRegex    = regexp.MustCompile(`[a-z]\d|\d[a-z]`)
+
+	usernameModel     *ngramModel
+	usernameModelOnce sync.Once
+)
+
+// DefaultRandomUsernameThreshold is the probability in percent used when a group has none set
+const DefaultRandomUsernameThreshold = 70
+
+// usernameAlphabet is the number of symbols that may follow a context: a-z and the end marker
+const usernameAlphabet = 27
+
+// ngramModel is a character trigram model with additive smoothing
+type ngramModel struct {
+	trigrams map[string]int
+	contexts map[string]int
+	weights  [4]float64 // bias, avg log prob, letter/digit switches, digit ratio
+}
+
+func loadUsernameModel() *ngramModel {
+	usernameModelOnce.Do(func() {
+		model := &ngramModel{
+			trigrams: make(map[string]int),
+			contexts: make(map[string]int),
+		}
+		scanner := bufio.NewScanner(strings.NewReader(usernameModelData))
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			if fields[0] == "w" && len(fields) == 5 {
+				for i := range model.weights {
+					model.weights[i], _ = strconv.ParseFloat(fields[i+1], 64)
+				}
+				continue
+			}
+			if len(fields) != 2 {
+				continue
+			}
+			count, err := strconv.Atoi(fields[1])
+			if err != nil {
+				continue
+			}
+			model.trigrams[fields[0]] += count
+			model.contexts[fields[0][:2]] += count
+		}
+		logger.Debugf("Loaded username model with %d trigrams", len(model.trigrams))
+		usernameModel = model
+	})
+	return usernameModel
+}
+
+// segmentLogProb returns the average log probability per character of a letter segment
+func (m *ngramModel) segmentLogProb(segment string) float64 {
+	s := "^^" + segment + "$"
+	total := 0.0
+	for i := 2; i < len(s); i++ {
+		trigram := float64(m.trigrams[s[i-2:i+1]])
+		context := float64(m.contexts[s[i-2:i]])
+		total += math.Log((trigram + 0.1) / (context + 0.1*usernameAlphabet))
+	}
+	return total / float64(len(s)-2)
+}
+
+// RandomUsernameProbability estimates how likely a username was randomly generated, from 0 to 1
+func RandomUsernameProbability(username string) float64 {
+	username = strings.ToLower(normalize.Skeleton(username))
+	if len(username) < 5 {
+		return 0
+	}
+	model := loadUsernameModel()
+
+	// average log probability of the letter parts, weighted by length
+	logProb, weight := -5.0, 0
+	segments := letterSegmentRegex.FindAllString(username, -1)
+	if len(segments) > 0 {
+		logProb = 0
+		for _, segment := range segments {
+			logProb += model.segmentLogProb(segment) * float64(len(segment)+1)
+			weight += len(segment) + 1
+		}
+		logProb /= float64(weight)
+	}
+
+	// switches between letters and digits, a trailing number such as a birth year is common
+	body := trailingDigitsRegex.ReplaceAllString(username, "")
+	switches := 0
+	for i := 1; i < len(body); i++ {
+		if letterDigitRegex.MatchString(body[i-1 : i+1]) {
+			switches++
+		}
+	}
+
+	digits := 0
+	for _, r := range username {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	digitRatio := float64(digits) / float64(len(username))
+
+	w := model.weights
+	z := w[0] + w[1]*logProb + w[2]*float64(switches) + w[3]*digitRatio
+	return 1 / (1 + math.Exp(-z))
+}
+
+// ExceedsRandomUsernameThreshold reports whether the username reaches the group's random username threshold
+func ExceedsRandomUsernameThreshold(group *models.GroupInfo, username string) bool {
+	if username == "" {
+		return false
+	}
+	threshold := group.RandomUsernameThreshold
+	if threshold <= 0 {
+		threshold = DefaultRandomUsernameThreshold
+	}
+	return RandomUsernameProbability(username)*100 >= float64(threshold)
+}
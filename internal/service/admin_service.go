@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mymmrac/telego"
+)
+
+// chatAdminsTTL is how long a fetched administrator list is reused
+const chatAdminsTTL = 10 * time.Minute
+
+type chatAdminsEntry struct {
+	admins  []telego.ChatMember
+	expires time.Time
+}
+
+// chatAdmins caches the administrator list of each chat
+var (
+	chatAdmins   = make(map[int64]chatAdminsEntry)
+	chatAdminsMu sync.Mutex
+)
+
+// GetChatAdministrators returns the administrators of a chat, cached for chatAdminsTTL
+func GetChatAdministrators(bot *telego.Bot, chatID int64) ([]telego.ChatMember, error) {
+	chatAdminsMu.Lock()
+	entry, ok := chatAdmins[chatID]
+	chatAdminsMu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.admins, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	admins, err := bot.GetChatAdministrators(ctx, &telego.GetChatAdministratorsParams{
+		ChatID: telego.ChatID{ID: chatID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chatAdminsMu.Lock()
+	chatAdmins[chatID] = chatAdminsEntry{admins: admins, expires: time.Now().Add(chatAdminsTTL)}
+	chatAdminsMu.Unlock()
+	return admins, nil
+}
+
+// InvalidateChatAdministrators drops the cached administrator list of a chat
+func InvalidateChatAdministrators(chatID int64) {
+	chatAdminsMu.Lock()
+	delete(chatAdmins, chatID)
+	chatAdminsMu.Unlock()
+}
+
+// IsAdminStatus reports whether a chat member status is creator or administrator
+func IsAdminStatus(member telego.ChatMember) bool {
+	if member == nil {
+		return false
+	}
+	status := member.MemberStatus()
+	return status == telego.MemberStatusCreator || status == telego.MemberStatusAdministrator
+}
+
+type linkedChatEntry struct {
+	chatID  int64
+	expires time.Time
+}
+
+// linkedChats caches the linked channel or discussion group of each chat
+var (
+	linkedChats   = make(map[int64]linkedChatEntry)
+	linkedChatsMu sync.Mutex
+)
+
+// GetLinkedChatID returns the ID of the channel linked to a discussion group, or 0, cached for chatAdminsTTL
+func GetLinkedChatID(bot *telego.Bot, chatID int64) (int64, error) {
+	linkedChatsMu.Lock()
+	entry, ok := linkedChats[chatID]
+	linkedChatsMu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.chatID, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	chatInfo, err := bot.GetChat(ctx, &telego.GetChatParams{
+		ChatID: telego.ChatID{ID: chatID},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	linkedChatsMu.Lock()
+	linkedChats[chatID] = linkedChatEntry{chatID: chatInfo.LinkedChatID, expires: time.Now().Add(chatAdminsTTL)}
+	linkedChatsMu.Unlock()
+	return chatInfo.LinkedChatID, nil
+}
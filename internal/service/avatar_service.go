@@ -0,0 +1,61 @@
+package service
+
+import (
+	"sync"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+)
+
+// avatarHashes caches the known spam avatar hashes by group, group 0 holds the global ones
+var (
+	avatarHashes   = make(map[int64][]uint64)
+	avatarHashesMu sync.RWMutex
+)
+
+// loadAvatarHashes loads all avatar hashes from the database into the cache
+func loadAvatarHashes() {
+	hashes, err := avatarRepository.GetAll()
+	if err != nil {
+		logger.Warningf("Error loading avatar hashes from database: %v", err)
+		return
+	}
+
+	avatarHashesMu.Lock()
+	defer avatarHashesMu.Unlock()
+	for _, hash := range hashes {
+		avatarHashes[hash.GroupID] = append(avatarHashes[hash.GroupID], hash.Hash)
+	}
+	logger.Infof("Loaded %d avatar hashes from database into cache", len(hashes))
+}
+
+// GetAvatarHashes returns the spam avatar hashes that apply to a group, including the global ones
+func GetAvatarHashes(groupID int64) []uint64 {
+	avatarHashesMu.RLock()
+	defer avatarHashesMu.RUnlock()
+
+	hashes := make([]uint64, 0, len(avatarHashes[groupID])+len(avatarHashes[0]))
+	hashes = append(hashes, avatarHashes[groupID]...)
+	if groupID != 0 {
+		hashes = append(hashes, avatarHashes[0]...)
+	}
+	return hashes
+}
+
+// AddAvatarHash stores the hash of a spam avatar, skipping hashes the group already has
+func AddAvatarHash(hash *models.AvatarHash) error {
+	avatarHashesMu.Lock()
+	for _, existing := range avatarHashes[hash.GroupID] {
+		if existing == hash.Hash {
+			avatarHashesMu.Unlock()
+			return nil
+		}
+	}
+	avatarHashes[hash.GroupID] = append(avatarHashes[hash.GroupID], hash.Hash)
+	avatarHashesMu.Unlock()
+
+	if avatarRepository != nil {
+		return avatarRepository.Create(hash)
+	}
+	return nil
+}
@@ -6,9 +6,8 @@ import (
 )
 
 // CreateBanRecord stores a new ban record for the user in a group
-func CreateBanRecord(groupID, userID int64, reason string) {
+func CreateBanRecord(record *models.BanRecord) {
 	if banRepository != nil {
-		record := &models.BanRecord{GroupID: groupID, UserID: userID, Reason: reason}
 		if err := banRepository.Create(record); err != nil {
 			logger.Warningf("Error creating ban record: %v", err)
 		}
@@ -23,6 +22,36 @@ func GetUserActiveBanRecords(userID int64, groupID int64) ([]*models.BanRecord,
 	return nil, nil
 }
 
+// GetLatestMessageBanRecord returns the newest record of a user in a group that keeps the offending message text
+func GetLatestMessageBanRecord(groupID, userID int64) (*models.BanRecord, error) {
+	if banRepository != nil {
+		return banRepository.GetLatestMessageRecord(groupID, userID)
+	}
+	return nil, nil
+}
+
+// SetBanRecordFeedback records the admin's judgement, "spam" or "ham", of the message behind a record
+func SetBanRecordFeedback(id uint, feedback string) {
+	if banRepository != nil {
+		if err := banRepository.SetFeedback(id, feedback); err != nil {
+			logger.Warningf("Error setting ban record feedback: %v", err)
+		}
+	}
+}
+
+// GetMessageExamples returns up to limit of the newest messages of a group an admin judged as spam or ham,
+// records removed for one of excludedReasons are left out
+func GetMessageExamples(groupID int64, spam bool, limit int, excludedReasons []string) []string {
+	if banRepository == nil {
+		return nil
+	}
+	texts, err := banRepository.GetMessageExamples(groupID, spam, limit, excludedReasons)
+	if err != nil {
+		logger.Warningf("Error getting message examples of group %d: %v", groupID, err)
+	}
+	return texts
+}
+
 // UnbanUserInGroup unban user in a group
 func UnbanUserInGroup(groupID, userID int64, unbannedBy string) {
 	if banRepository != nil {
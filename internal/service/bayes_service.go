@@ -0,0 +1,76 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"tg-antispam/internal/bayes"
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+)
+
+// spamClassifier is the naive Bayes model shared by all groups
+var spamClassifier = bayes.New()
+
+// bayesTrainMu serializes training with saving the changed counts, the counts are absolute
+// and an older snapshot written after a newer one would leave the database behind the model
+var bayesTrainMu sync.Mutex
+
+// loadBayesModel loads the token counts of the spam classifier from the database
+func loadBayesModel() {
+	tokens, err := bayesRepository.GetAll()
+	if err != nil {
+		logger.Warningf("Error loading bayes model from database: %v", err)
+		return
+	}
+
+	for _, token := range tokens {
+		if token.Spam == 0 && token.Ham == 0 {
+			continue
+		}
+		spamClassifier.Set(token.Token, bayes.Counts{Spam: token.Spam, Ham: token.Ham})
+	}
+	spam, ham, vocabulary := spamClassifier.Stats()
+	logger.Infof("Loaded bayes model from database: %d spam, %d ham messages, %d tokens", spam, ham, vocabulary)
+}
+
+// BayesSpamProbability returns the probability that text is spam, ok is false while the model can't tell
+func BayesSpamProbability(text string) (float64, bool) {
+	return spamClassifier.SpamProbability(text)
+}
+
+// TrainBayes teaches the classifier a message labeled as spam or ham
+func TrainBayes(text string, spam bool) {
+	bayesTrainMu.Lock()
+	defer bayesTrainMu.Unlock()
+	saveBayesCounts(spamClassifier.Learn(text, spam))
+}
+
+// RetrainBayes moves a message previously learned with the opposite label to the given label
+func RetrainBayes(text string, spam bool) {
+	bayesTrainMu.Lock()
+	defer bayesTrainMu.Unlock()
+	saveBayesCounts(spamClassifier.Unlearn(text, !spam))
+	saveBayesCounts(spamClassifier.Learn(text, spam))
+}
+
+// BayesStats returns the number of learned spam and ham messages and the vocabulary size
+func BayesStats() (spam, ham, vocabulary int) {
+	return spamClassifier.Stats()
+}
+
+// saveBayesCounts persists the changed token counts
+func saveBayesCounts(changed map[string]bayes.Counts) {
+	if bayesRepository == nil || len(changed) == 0 {
+		return
+	}
+
+	now := time.Now()
+	tokens := make([]*models.BayesToken, 0, len(changed))
+	for token, counts := range changed {
+		tokens = append(tokens, &models.BayesToken{Token: token, Spam: counts.Spam, Ham: counts.Ham, UpdatedAt: now})
+	}
+	if err := bayesRepository.Upsert(tokens); err != nil {
+		logger.Warningf("Error saving bayes model: %v", err)
+	}
+}
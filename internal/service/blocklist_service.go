@@ -0,0 +1,58 @@
+package service
+
+import (
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+)
+
+var blocklistManager = models.NewBlocklistManager()
+
+// loadBlocklistPatterns loads all patterns from the database into the cache
+func loadBlocklistPatterns() {
+	patterns, err := blocklistRepository.GetAll()
+	if err != nil {
+		logger.Warningf("Error loading blocklist patterns from database: %v", err)
+		return
+	}
+
+	for _, pattern := range patterns {
+		if err := pattern.Compile(); err != nil {
+			logger.Warningf("Skipping invalid blocklist pattern %d: %v", pattern.ID, err)
+			continue
+		}
+		blocklistManager.Add(pattern)
+	}
+	logger.Infof("Loaded %d blocklist patterns from database into cache", len(patterns))
+}
+
+// GetBlocklistPatterns returns the custom patterns of a group
+func GetBlocklistPatterns(groupID int64) []*models.BlocklistPattern {
+	return blocklistManager.Get(groupID)
+}
+
+// AddBlocklistPattern validates and stores a pattern
+func AddBlocklistPattern(pattern *models.BlocklistPattern) error {
+	if err := pattern.Compile(); err != nil {
+		return err
+	}
+	if blocklistRepository != nil {
+		if err := blocklistRepository.Create(pattern); err != nil {
+			return err
+		}
+	}
+	blocklistManager.Add(pattern)
+	return nil
+}
+
+// RemoveBlocklistPattern deletes a pattern of a group, returning false if it does not exist
+func RemoveBlocklistPattern(groupID int64, id uint) (bool, error) {
+	if !blocklistManager.Remove(groupID, id) {
+		return false, nil
+	}
+	if blocklistRepository != nil {
+		if err := blocklistRepository.Delete(groupID, id); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
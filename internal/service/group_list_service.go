@@ -0,0 +1,54 @@
+package service
+
+import (
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+)
+
+var groupListManager = models.NewGroupListManager()
+
+// loadGroupLists loads all list entries from the database into the cache
+func loadGroupLists() {
+	entries, err := groupListRepository.GetAll()
+	if err != nil {
+		logger.Warningf("Error loading group lists from database: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		groupListManager.Add(entry.GroupID, entry.List, entry.Value)
+	}
+	logger.Infof("Loaded %d group list entries from database into cache", len(entries))
+}
+
+// GetGroupList returns the values of one of a group's lists
+func GetGroupList(groupID int64, list string) []string {
+	return groupListManager.Get(groupID, list)
+}
+
+// AddGroupListValue adds a value to a group's list, returning false if it is already present
+func AddGroupListValue(groupID int64, list, value string, createdBy int64) (bool, error) {
+	if !groupListManager.Add(groupID, list, value) {
+		return false, nil
+	}
+	if groupListRepository != nil {
+		entry := &models.GroupListEntry{GroupID: groupID, List: list, Value: value, CreatedBy: createdBy}
+		if err := groupListRepository.Create(entry); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// RemoveGroupListValue removes a value from a group's list, returning false if it does not exist
+func RemoveGroupListValue(groupID int64, list, value string) (bool, error) {
+	if !groupListManager.Remove(groupID, list, value) {
+		return false, nil
+	}
+	if groupListRepository != nil {
+		if err := groupListRepository.Delete(groupID, list, value); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
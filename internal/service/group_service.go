@@ -38,16 +38,49 @@ func GetGroupInfo(bot *telego.Bot, groupID int64, create bool) *models.GroupInfo
 
 	logger.Infof("Creating new group info for groupID: %d", groupID)
 	groupInfo = &models.GroupInfo{
-		GroupID:            groupID,
-		IsAdmin:            false,
-		AdminID:            -1,
-		EnableNotification: true,
-		BanPremium:         globalConfig.Antispam.BanPremium,
-		BanEmojiName:       globalConfig.Antispam.BanEmojiName,
-		BanRandomUsername:  globalConfig.Antispam.BanRandomUsername,
-		BanBioLink:         globalConfig.Antispam.BanBioLink,
-		EnableCAS:          globalConfig.Antispam.UseCAS,
-		Language:           "zh_CN",
+		GroupID:                 groupID,
+		IsAdmin:                 false,
+		AdminID:                 -1,
+		EnableNotification:      true,
+		BanPremium:              globalConfig.Antispam.BanPremium,
+		BanEmojiName:            globalConfig.Antispam.BanEmojiName,
+		BanRandomUsername:       globalConfig.Antispam.BanRandomUsername,
+		BanBioLink:              globalConfig.Antispam.BanBioLink,
+		EnableCAS:               globalConfig.Antispam.UseCAS,
+		RestrictScore:           globalConfig.Antispam.RestrictScore,
+		BanScore:                globalConfig.Antispam.BanScore,
+		EmojiMinCount:           globalConfig.Antispam.EmojiMinCount,
+		EmojiRatio:              globalConfig.Antispam.EmojiRatio,
+		RandomUsernameThreshold: globalConfig.Antispam.RandomUsernameThreshold,
+		MinAccountAgeDays:       globalConfig.Antispam.MinAccountAgeDays,
+		AccountAgeAction:        globalConfig.Antispam.AccountAgeAction,
+		BanAvatarHash:           globalConfig.Antispam.BanAvatarHash,
+		BanNoAvatar:             globalConfig.Antispam.BanNoAvatar,
+		BanImpersonation:        globalConfig.Antispam.BanImpersonation,
+		BanInvisibleChars:       globalConfig.Antispam.BanInvisibleChars,
+		BlocklistProviders:      globalConfig.Antispam.DefaultProviders,
+		EnableMessageScan:       globalConfig.Antispam.EnableMessageScan,
+		MessageProviderCheck:    globalConfig.Antispam.MessageProviderCheck,
+		EnableAicheck:           globalConfig.Antispam.EnableAiCheck,
+		EnableAiProfile:         globalConfig.Antispam.EnableAiProfile,
+		MessageAction:           globalConfig.Antispam.MessageAction,
+		EnableBayes:             globalConfig.Antispam.EnableBayes,
+		FloodMessages:           globalConfig.Antispam.FloodMessages,
+		FloodMedia:              globalConfig.Antispam.FloodMedia,
+		FloodMentions:           globalConfig.Antispam.FloodMentions,
+		FloodWindowSec:          globalConfig.Antispam.FloodWindowSec,
+		FloodAction:             globalConfig.Antispam.FloodAction,
+		FloodMuteMinutes:        globalConfig.Antispam.FloodMuteMinutes,
+		DuplicateGroups:         globalConfig.Antispam.DuplicateGroups,
+		DuplicateUsers:          globalConfig.Antispam.DuplicateUsers,
+		EnableEditScan:          globalConfig.Antispam.EnableEditScan,
+		EditWindowMinutes:       globalConfig.Antispam.EditWindowMinutes,
+		BlockInlineBots:         globalConfig.Antispam.BlockInlineBots,
+		ForwardChannelPolicy:    globalConfig.Antispam.ForwardChannelPolicy,
+		ForwardChannelAction:    globalConfig.Antispam.ForwardChannelAction,
+		BlockHiddenForwards:     globalConfig.Antispam.BlockHiddenForwards,
+		HiddenForwardAction:     globalConfig.Antispam.HiddenForwardAction,
+		Language:                "zh_CN",
 	}
 
 	// get group name and link from telegram
@@ -136,13 +169,7 @@ func DeleteGroupInfo(groupID int64) error {
 }
 
 func GetBotPromoterID(bot *telego.Bot, chatID int64) (int64, bool) {
-	// 创建带超时的context
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	admins, err := bot.GetChatAdministrators(ctx, &telego.GetChatAdministratorsParams{
-		ChatID: telego.ChatID{ID: chatID},
-	})
+	admins, err := GetChatAdministrators(bot, chatID)
 	if err != nil {
 		logger.Warningf("Error getting chat administrators for chat %d: %v", chatID, err)
 		return 0, false
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"tg-antispam/internal/logger"
+	"tg-antispam/internal/models"
+)
+
+// maxReputationEntries bounds the in-memory reputation cache. Beyond it expired entries are swept,
+// then arbitrary ones until half of it is free; an evicted user is looked up again.
+const maxReputationEntries = 200000
+
+type reputationKey struct {
+	userID   int64
+	provider string
+}
+
+// reputationCache holds the provider verdicts by user, backed by the database when it is enabled
+var (
+	reputationCache   = make(map[reputationKey]*models.ReputationEntry)
+	reputationCacheMu sync.RWMutex
+)
+
+// loadReputation removes expired entries from the database and loads the valid ones into the cache
+func loadReputation() {
+	now := time.Now()
+	if deleted, err := reputationRepository.DeleteExpired(now); err != nil {
+		logger.Warningf("Error deleting expired reputation entries: %v", err)
+	} else if deleted > 0 {
+		logger.Infof("Deleted %d expired reputation entries", deleted)
+	}
+
+	entries, err := reputationRepository.GetValid(now)
+	if err != nil {
+		logger.Warningf("Error loading reputation entries from database: %v", err)
+		return
+	}
+
+	reputationCacheMu.Lock()
+	defer reputationCacheMu.Unlock()
+	for _, entry := range entries {
+		reputationCache[reputationKey{entry.UserID, entry.Provider}] = entry
+	}
+	logger.Infof("Loaded %d reputation entries from database into cache", len(entries))
+}
+
+// GetReputation returns the cached verdict of a provider for a user.
+// ok is false when the user has not been looked up or the entry has expired.
+func GetReputation(userID int64, provider string) (listed bool, ok bool) {
+	reputationCacheMu.RLock()
+	entry, found := reputationCache[reputationKey{userID, provider}]
+	reputationCacheMu.RUnlock()
+	if !found || entry.Expired(time.Now()) {
+		return false, false
+	}
+	return entry.Listed, true
+}
+
+// sweepReputation drops expired entries, and arbitrary ones if the cache is still full. The caller holds the lock.
+func sweepReputation(now time.Time) {
+	for key, cached := range reputationCache {
+		if cached.Expired(now) {
+			delete(reputationCache, key)
+		}
+	}
+	for key := range reputationCache {
+		if len(reputationCache) < maxReputationEntries/2 {
+			break
+		}
+		delete(reputationCache, key)
+	}
+}
+
+// SetReputation caches the verdict of a provider for a user for ttl
+func SetReputation(userID int64, provider string, listed bool, ttl time.Duration) {
+	now := time.Now()
+	entry := &models.ReputationEntry{
+		UserID:    userID,
+		Provider:  provider,
+		Listed:    listed,
+		ExpiresAt: now.Add(ttl),
+		UpdatedAt: now,
+	}
+
+	reputationCacheMu.Lock()
+	if len(reputationCache) >= maxReputationEntries {
+		sweepReputation(now)
+	}
+	reputationCache[reputationKey{userID, provider}] = entry
+	reputationCacheMu.Unlock()
+
+	if reputationRepository != nil {
+		if err := reputationRepository.Save(entry); err != nil {
+			logger.Warningf("Error saving reputation of user %d from %s: %v", userID, provider, err)
+		}
+	}
+}
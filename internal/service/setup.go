@@ -13,6 +13,11 @@ var (
 	groupRepository      *storage.GroupRepository
 	banRepository        *storage.BanRepository
 	pendingMsgRepository *storage.PendingMsgRepository
+	blocklistRepository  *storage.BlocklistRepository
+	avatarRepository     *storage.AvatarRepository
+	groupListRepository  *storage.GroupListRepository
+	reputationRepository *storage.ReputationRepository
+	bayesRepository      *storage.BayesRepository
 	globalConfig         *config.Config
 )
 
@@ -62,6 +67,36 @@ func InitRepositories() {
 		if err := pendingMsgRepository.MigrateTable(); err != nil {
 			logger.Warningf("Error migrating PendingMessageDeletion table: %v", err)
 		}
+		// Initialize BlocklistPattern table and load patterns into the cache
+		blocklistRepository = storage.NewBlocklistRepository(storage.DB)
+		if err := blocklistRepository.MigrateTable(); err != nil {
+			logger.Warningf("Error migrating BlocklistPattern table: %v", err)
+		}
+		loadBlocklistPatterns()
+		// Initialize AvatarHash table and load hashes into the cache
+		avatarRepository = storage.NewAvatarRepository(storage.DB)
+		if err := avatarRepository.MigrateTable(); err != nil {
+			logger.Warningf("Error migrating AvatarHash table: %v", err)
+		}
+		loadAvatarHashes()
+		// Initialize GroupListEntry table and load the allow/deny lists into the cache
+		groupListRepository = storage.NewGroupListRepository(storage.DB)
+		if err := groupListRepository.MigrateTable(); err != nil {
+			logger.Warningf("Error migrating GroupListEntry table: %v", err)
+		}
+		loadGroupLists()
+		// Initialize ReputationEntry table and load the unexpired provider verdicts into the cache
+		reputationRepository = storage.NewReputationRepository(storage.DB)
+		if err := reputationRepository.MigrateTable(); err != nil {
+			logger.Warningf("Error migrating ReputationEntry table: %v", err)
+		}
+		loadReputation()
+		// Initialize BayesToken table and load the spam classifier
+		bayesRepository = storage.NewBayesRepository(storage.DB)
+		if err := bayesRepository.MigrateTable(); err != nil {
+			logger.Warningf("Error migrating BayesToken table: %v", err)
+		}
+		loadBayesModel()
 	}
 }
 
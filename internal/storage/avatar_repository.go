@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"tg-antispam/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AvatarRepository handles database operations for AvatarHash
+type AvatarRepository struct {
+	db *gorm.DB
+}
+
+// NewAvatarRepository creates a new AvatarRepository
+func NewAvatarRepository(db *gorm.DB) *AvatarRepository {
+	return &AvatarRepository{db: db}
+}
+
+// MigrateTable ensures the AvatarHash table exists
+func (r *AvatarRepository) MigrateTable() error {
+	return r.db.AutoMigrate(&models.AvatarHash{})
+}
+
+// Create inserts a new avatar hash
+func (r *AvatarRepository) Create(hash *models.AvatarHash) error {
+	return r.db.Create(hash).Error
+}
+
+// GetAll returns the avatar hashes of all groups, including the global ones
+func (r *AvatarRepository) GetAll() ([]*models.AvatarHash, error) {
+	var hashes []*models.AvatarHash
+	result := r.db.Find(&hashes)
+	return hashes, result.Error
+}
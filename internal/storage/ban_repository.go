@@ -8,6 +8,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// inactiveActions are record actions that only log what happened and never are active bans
+var inactiveActions = []string{"delete", "mute"}
+
 // BanRepository handles database operations for BanRecord
 type BanRepository struct {
 	db *gorm.DB
@@ -28,18 +31,66 @@ func (r *BanRepository) Create(record *models.BanRecord) error {
 	return r.db.Create(record).Error
 }
 
-// GetActiveRecordsByUser returns all non-unbanned records for a user
+// GetActiveRecordsByUser returns all non-unbanned records for a user.
+// Deleted message records and temporary mutes are skipped, they never keep a user restricted.
 func (r *BanRepository) GetActiveRecordsByUser(userID int64, groupID int64) ([]*models.BanRecord, error) {
 	var records []*models.BanRecord
 	var result *gorm.DB
 	if groupID != -1 {
-		result = r.db.Where("user_id = ? AND group_id = ? AND is_unbanned = ?", userID, groupID, false).Find(&records)
+		result = r.db.Where("user_id = ? AND group_id = ? AND is_unbanned = ? AND action NOT IN ?", userID, groupID, false, inactiveActions).Find(&records)
 	} else {
-		result = r.db.Where("user_id = ? AND is_unbanned = ?", userID, false).Find(&records)
+		result = r.db.Where("user_id = ? AND is_unbanned = ? AND action NOT IN ?", userID, false, inactiveActions).Find(&records)
 	}
 	return records, result.Error
 }
 
+// GetLatestMessageRecord returns the newest record of a user in a group that keeps the offending message text
+func (r *BanRepository) GetLatestMessageRecord(groupID, userID int64) (*models.BanRecord, error) {
+	var record models.BanRecord
+	result := r.db.Where("group_id = ? AND user_id = ? AND message_text <> ?", groupID, userID, "").Order("id DESC").Limit(1).Find(&record)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// SetFeedback records the admin's judgement of the message behind a record
+func (r *BanRepository) SetFeedback(id uint, feedback string) error {
+	return r.db.Model(&models.BanRecord{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"feedback": feedback, "updated_at": time.Now()}).Error
+}
+
+// GetMessageExamples returns the newest message texts of a group an admin judged as spam (ban confirmed)
+// or ham (unbanned). Records removed for one of excludedReasons are left out.
+func (r *BanRepository) GetMessageExamples(groupID int64, spam bool, limit int, excludedReasons []string) ([]string, error) {
+	feedback := "ham"
+	if spam {
+		feedback = "spam"
+	}
+	query := r.db.Model(&models.BanRecord{}).Where("group_id = ? AND message_text <> ? AND feedback = ?", groupID, "", feedback)
+	if len(excludedReasons) > 0 {
+		query = query.Where("reason NOT IN ?", excludedReasons)
+	}
+	var texts []string
+	result := query.Order("id DESC").Limit(limit).Pluck("message_text", &texts)
+	return texts, result.Error
+}
+
+// ReasonCount is the number of records with a reason
+type ReasonCount struct {
+	Reason string
+	Count  int64
+}
+
+// CountEditedByReason counts the records of a group flagged after an edit since the given time, by reason
+func (r *BanRepository) CountEditedByReason(groupID int64, since time.Time) ([]ReasonCount, error) {
+	var counts []ReasonCount
+	result := r.db.Model(&models.BanRecord{}).Select("reason, COUNT(*) AS count").
+		Where("group_id = ? AND edited = ? AND created_at >= ?", groupID, true, since).
+		Group("reason").Order("count DESC").Scan(&counts)
+	return counts, result.Error
+}
+
 // UnbanUserByGroup unban user in a group
 func (r *BanRepository) UnbanUserByGroup(groupID, userID int64, unbannedBy string) error {
 	result := r.db.Model(&models.BanRecord{}).
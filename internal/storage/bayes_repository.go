@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"tg-antispam/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BayesRepository handles database operations for BayesToken
+type BayesRepository struct {
+	db *gorm.DB
+}
+
+// NewBayesRepository creates a new BayesRepository
+func NewBayesRepository(db *gorm.DB) *BayesRepository {
+	return &BayesRepository{db: db}
+}
+
+// MigrateTable ensures the BayesToken table exists
+func (r *BayesRepository) MigrateTable() error {
+	return r.db.AutoMigrate(&models.BayesToken{})
+}
+
+// Upsert inserts the tokens or replaces their counts
+func (r *BayesRepository) Upsert(tokens []*models.BayesToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"spam", "ham", "updated_at"}),
+	}).CreateInBatches(tokens, 500).Error
+}
+
+// GetAll returns all tokens of the model
+func (r *BayesRepository) GetAll() ([]*models.BayesToken, error) {
+	var tokens []*models.BayesToken
+	result := r.db.Find(&tokens)
+	return tokens, result.Error
+}
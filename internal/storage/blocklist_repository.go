@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"tg-antispam/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BlocklistRepository handles database operations for BlocklistPattern
+type BlocklistRepository struct {
+	db *gorm.DB
+}
+
+// NewBlocklistRepository creates a new BlocklistRepository
+func NewBlocklistRepository(db *gorm.DB) *BlocklistRepository {
+	return &BlocklistRepository{db: db}
+}
+
+// MigrateTable ensures the BlocklistPattern table exists
+func (r *BlocklistRepository) MigrateTable() error {
+	return r.db.AutoMigrate(&models.BlocklistPattern{})
+}
+
+// Create inserts a new pattern
+func (r *BlocklistRepository) Create(pattern *models.BlocklistPattern) error {
+	return r.db.Create(pattern).Error
+}
+
+// Delete removes a pattern of a group by ID
+func (r *BlocklistRepository) Delete(groupID int64, id uint) error {
+	return r.db.Where("group_id = ? AND id = ?", groupID, id).Delete(&models.BlocklistPattern{}).Error
+}
+
+// GetAll returns the patterns of all groups
+func (r *BlocklistRepository) GetAll() ([]*models.BlocklistPattern, error) {
+	var patterns []*models.BlocklistPattern
+	result := r.db.Order("id").Find(&patterns)
+	return patterns, result.Error
+}
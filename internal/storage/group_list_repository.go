@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"tg-antispam/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GroupListRepository handles database operations for GroupListEntry
+type GroupListRepository struct {
+	db *gorm.DB
+}
+
+// NewGroupListRepository creates a new GroupListRepository
+func NewGroupListRepository(db *gorm.DB) *GroupListRepository {
+	return &GroupListRepository{db: db}
+}
+
+// MigrateTable ensures the GroupListEntry table exists
+func (r *GroupListRepository) MigrateTable() error {
+	return r.db.AutoMigrate(&models.GroupListEntry{})
+}
+
+// Create inserts a new list entry
+func (r *GroupListRepository) Create(entry *models.GroupListEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// Delete removes a value from a group's list
+func (r *GroupListRepository) Delete(groupID int64, list, value string) error {
+	return r.db.Where("group_id = ? AND list = ? AND value = ?", groupID, list, value).Delete(&models.GroupListEntry{}).Error
+}
+
+// GetAll returns the list entries of all groups
+func (r *GroupListRepository) GetAll() ([]*models.GroupListEntry, error) {
+	var entries []*models.GroupListEntry
+	result := r.db.Order("id").Find(&entries)
+	return entries, result.Error
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"time"
+
+	"tg-antispam/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReputationRepository handles database operations for ReputationEntry
+type ReputationRepository struct {
+	db *gorm.DB
+}
+
+// NewReputationRepository creates a new ReputationRepository
+func NewReputationRepository(db *gorm.DB) *ReputationRepository {
+	return &ReputationRepository{db: db}
+}
+
+// MigrateTable ensures the ReputationEntry table exists
+func (r *ReputationRepository) MigrateTable() error {
+	return r.db.AutoMigrate(&models.ReputationEntry{})
+}
+
+// Save inserts or replaces the entry of a user and provider
+func (r *ReputationRepository) Save(entry *models.ReputationEntry) error {
+	return r.db.Save(entry).Error
+}
+
+// GetValid returns the entries that have not expired at now
+func (r *ReputationRepository) GetValid(now time.Time) ([]*models.ReputationEntry, error) {
+	var entries []*models.ReputationEntry
+	result := r.db.Where("expires_at > ?", now).Find(&entries)
+	return entries, result.Error
+}
+
+// DeleteExpired removes the entries that expired before now
+func (r *ReputationRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.ReputationEntry{})
+	return result.RowsAffected, result.Error
+}
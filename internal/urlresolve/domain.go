@@ -0,0 +1,49 @@
+package urlresolve
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// domainRegex matches a lower-case host name with at least two labels
+var domainRegex = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9-]{2,63}$`)
+
+// Host returns the lower-case host name of link without port and trailing dot, or "" if it has none
+func Host(link string) string {
+	normalized, err := NormalizeURL(link)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
+
+// NormalizeDomain validates a domain for the domain allow and deny lists. Links and "*." prefixes
+// are reduced to the host, e.g. "https://www.Example.com/path" becomes "www.example.com".
+func NormalizeDomain(value string) (string, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "*.")
+	domain := Host(value)
+	if !domainRegex.MatchString(domain) {
+		return "", false
+	}
+	return domain, true
+}
+
+// MatchDomain reports whether host is domain or one of its subdomains
+func MatchDomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// MatchAnyDomain returns the first of domains that host belongs to, or ""
+func MatchAnyDomain(host string, domains []string) string {
+	for _, domain := range domains {
+		if MatchDomain(host, domain) {
+			return domain
+		}
+	}
+	return ""
+}
@@ -0,0 +1,190 @@
+// Package urlresolve follows the redirects of links, such as URL shorteners, to find
+// the destination a message really points to, and matches hosts against domain lists.
+package urlresolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnsupportedURL is returned for links that are not http or https
+var ErrUnsupportedURL = errors.New("unsupported URL")
+
+// maxCacheEntries bounds the result cache, expired entries are swept beyond it
+const maxCacheEntries = 10000
+
+// failureTTL bounds how long a failed resolution is cached, the link may work again soon
+const failureTTL = time.Minute
+
+// userAgent is sent with every request, some shorteners refuse requests without one
+const userAgent = "Mozilla/5.0 (compatible; tg-antispam link check)"
+
+// Options controls how far and how long links are followed
+type Options struct {
+	MaxHops  int           // redirects followed before giving up, the chain then ends at the last URL reached
+	Timeout  time.Duration // time allowed to resolve one link, all hops included
+	CacheTTL time.Duration // how long a resolution is reused for the same link
+}
+
+// Resolution is the redirect chain of a link, starting with the link itself
+type Resolution struct {
+	Chain     []string
+	Truncated bool // the hop limit was reached before a final destination
+}
+
+// Final returns the last URL of the chain
+func (r Resolution) Final() string {
+	return r.Chain[len(r.Chain)-1]
+}
+
+// Hosts returns the hosts of the chain in order
+func (r Resolution) Hosts() []string {
+	hosts := make([]string, 0, len(r.Chain))
+	for _, link := range r.Chain {
+		if host := Host(link); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+type cacheEntry struct {
+	resolution Resolution
+	err        error
+	expires    time.Time
+}
+
+// Resolver follows redirects with HEAD requests and caches the chains
+type Resolver struct {
+	client  *http.Client
+	options Options
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// New creates a resolver sending its requests with client. Redirects are followed by the resolver itself,
+// so client's own redirect policy is replaced.
+func New(client *http.Client, options Options) *Resolver {
+	c := *client
+	c.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if options.MaxHops <= 0 {
+		options.MaxHops = 5
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	return &Resolver{client: &c, options: options, cache: make(map[string]cacheEntry)}
+}
+
+// Resolve returns the redirect chain of link. On an error the chain holds the URLs reached so far.
+func (r *Resolver) Resolve(ctx context.Context, link string) (Resolution, error) {
+	start, err := NormalizeURL(link)
+	if err != nil {
+		return Resolution{Chain: []string{link}}, err
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	entry, ok := r.cache[start]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.resolution, entry.err
+	}
+
+	resolution, err := r.follow(ctx, start)
+
+	ttl := r.options.CacheTTL
+	if err != nil {
+		ttl = min(ttl, failureTTL)
+	}
+	if ttl > 0 {
+		r.mu.Lock()
+		if len(r.cache) >= maxCacheEntries {
+			r.sweepCache(now)
+		}
+		r.cache[start] = cacheEntry{resolution: resolution, err: err, expires: now.Add(ttl)}
+		r.mu.Unlock()
+	}
+	return resolution, err
+}
+
+// follow sends HEAD requests along the redirects of start until a response that is not a redirect
+func (r *Resolver) follow(ctx context.Context, start string) (Resolution, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.options.Timeout)
+	defer cancel()
+
+	resolution := Resolution{Chain: []string{start}}
+	current := start
+	for hop := 0; ; hop++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, current, nil)
+		if err != nil {
+			return resolution, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return resolution, err
+		}
+		resp.Body.Close()
+
+		location := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 || location == "" {
+			return resolution, nil
+		}
+		next, err := resp.Request.URL.Parse(location)
+		if err != nil {
+			return resolution, fmt.Errorf("invalid redirect from %s: %w", current, err)
+		}
+		if next.Scheme != "http" && next.Scheme != "https" {
+			return resolution, fmt.Errorf("%w: redirect from %s to %s", ErrUnsupportedURL, current, next.Scheme)
+		}
+		if hop == r.options.MaxHops {
+			resolution.Truncated = true
+			return resolution, nil
+		}
+		current = next.String()
+		resolution.Chain = append(resolution.Chain, current)
+	}
+}
+
+// sweepCache drops expired entries, and arbitrary ones if the cache is still full. The caller holds the lock.
+func (r *Resolver) sweepCache(now time.Time) {
+	for key, entry := range r.cache {
+		if !now.Before(entry.expires) {
+			delete(r.cache, key)
+		}
+	}
+	for key := range r.cache {
+		if len(r.cache) < maxCacheEntries/2 {
+			break
+		}
+		delete(r.cache, key)
+	}
+}
+
+// NormalizeURL returns link as an absolute http or https URL, links written without a scheme like "bit.ly/x" get https
+func NormalizeURL(link string) (string, error) {
+	link = strings.TrimSpace(link)
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedURL, link)
+	}
+	u.Fragment = ""
+	return u.String(), nil
+}
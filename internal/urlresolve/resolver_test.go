@@ -0,0 +1,161 @@
+package urlresolve
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRedirectChain starts a server that plays every host of a shortener redirect chain and a resolver
+// whose connections all go to it, https links too: DialTLSContext hands out a plain connection
+func newRedirectChain(t *testing.T) (*Resolver, *atomic.Int32) {
+	t.Helper()
+
+	requests := new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method != http.MethodHead {
+			http.Error(w, "unexpected method "+r.Method, http.StatusMethodNotAllowed)
+			return
+		}
+		switch r.Host + r.URL.Path {
+		case "bit.ly/abc":
+			http.Redirect(w, r, "https://t.ly/x", http.StatusMovedPermanently)
+		case "t.ly/x":
+			http.Redirect(w, r, "/y", http.StatusFound)
+		case "t.ly/y":
+			http.Redirect(w, r, "http://spam.example.com/landing", http.StatusTemporaryRedirect)
+		case "spam.example.com/landing", "news.example.org/":
+			w.WriteHeader(http.StatusOK)
+		case "loop.example/":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "slow.example/":
+			time.Sleep(600 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		case "ftp.example/":
+			http.Redirect(w, r, "ftp://files.example/", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	client := &http.Client{Transport: &http.Transport{DialContext: dial, DialTLSContext: dial}}
+	return New(client, Options{MaxHops: 3, Timeout: 300 * time.Millisecond, CacheTTL: time.Minute}), requests
+}
+
+func TestResolveRedirectChain(t *testing.T) {
+	resolver, _ := newRedirectChain(t)
+
+	resolution, err := resolver.Resolve(context.Background(), "bit.ly/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://bit.ly/abc -> https://t.ly/x -> https://t.ly/y -> http://spam.example.com/landing"
+	if got := strings.Join(resolution.Chain, " -> "); got != want || resolution.Truncated {
+		t.Fatalf("chain %s (truncated %v), want %s", got, resolution.Truncated, want)
+	}
+	if host := Host(resolution.Final()); host != "spam.example.com" {
+		t.Errorf("final host %s, want spam.example.com", host)
+	}
+	if hosts := strings.Join(resolution.Hosts(), ","); hosts != "bit.ly,t.ly,t.ly,spam.example.com" {
+		t.Errorf("hosts %s", hosts)
+	}
+}
+
+func TestResolveNoRedirect(t *testing.T) {
+	resolver, _ := newRedirectChain(t)
+
+	resolution, err := resolver.Resolve(context.Background(), "http://news.example.org")
+	if err != nil || len(resolution.Chain) != 1 {
+		t.Fatalf("chain %v, %v", resolution.Chain, err)
+	}
+}
+
+func TestResolveCache(t *testing.T) {
+	resolver, requests := newRedirectChain(t)
+
+	if _, err := resolver.Resolve(context.Background(), "bit.ly/abc"); err != nil {
+		t.Fatal(err)
+	}
+	before := requests.Load()
+	if _, err := resolver.Resolve(context.Background(), "https://bit.ly/abc"); err != nil {
+		t.Fatal(err)
+	}
+	if sent := requests.Load() - before; sent != 0 {
+		t.Errorf("%d requests sent for a cached link", sent)
+	}
+}
+
+func TestResolveHopLimit(t *testing.T) {
+	resolver, _ := newRedirectChain(t)
+
+	resolution, err := resolver.Resolve(context.Background(), "http://loop.example/")
+	if err != nil || !resolution.Truncated || len(resolution.Chain) != 4 {
+		t.Fatalf("chain %v (truncated %v), %v", resolution.Chain, resolution.Truncated, err)
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	resolver, _ := newRedirectChain(t)
+
+	resolution, err := resolver.Resolve(context.Background(), "http://slow.example/")
+	if err == nil || len(resolution.Chain) != 1 {
+		t.Fatalf("chain %v, %v, want a timeout", resolution.Chain, err)
+	}
+}
+
+func TestResolveUnsupported(t *testing.T) {
+	resolver, _ := newRedirectChain(t)
+
+	for _, link := range []string{"http://ftp.example/", "tg://resolve?domain=spam"} {
+		if _, err := resolver.Resolve(context.Background(), link); !errors.Is(err, ErrUnsupportedURL) {
+			t.Errorf("%s: %v, want ErrUnsupportedURL", link, err)
+		}
+	}
+}
+
+func TestPublicClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resolver := New(NewPublicClient(time.Second), Options{})
+	if _, err := resolver.Resolve(context.Background(), server.URL); !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("%v, want ErrPrivateAddress", err)
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := map[string]string{
+		"Example.com":                  "example.com",
+		"*.example.com":                "example.com",
+		"https://www.Example.com/path": "www.example.com",
+		"localhost":                    "",
+		"not a domain":                 "",
+	}
+	for value, want := range tests {
+		got, valid := NormalizeDomain(value)
+		if got != want || valid != (want != "") {
+			t.Errorf("NormalizeDomain(%q) = %q, %v, want %q", value, got, valid, want)
+		}
+	}
+}
+
+func TestMatchAnyDomain(t *testing.T) {
+	domains := []string{"example.org", "example.com"}
+	if got := MatchAnyDomain("spam.example.com", domains); got != "example.com" {
+		t.Errorf("subdomain matched %q", got)
+	}
+	if got := MatchAnyDomain("notexample.com", domains); got != "" {
+		t.Errorf("notexample.com matched %q", got)
+	}
+}
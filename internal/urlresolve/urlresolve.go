@@ -0,0 +1,93 @@
+package urlresolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"tg-antispam/internal/config"
+	"tg-antispam/internal/logger"
+)
+
+// ErrPrivateAddress is returned when a link leads to a loopback, private or otherwise non-public address
+var ErrPrivateAddress = errors.New("non-public address")
+
+var (
+	resolver *Resolver
+	maxURLs  = 3
+)
+
+// Initialize sets up the shared resolver, links are not resolved if it is disabled
+func Initialize(cfg config.UrlResolveConfig) {
+	if !cfg.Enabled {
+		resolver = nil
+		logger.Info("URL resolution is disabled")
+		return
+	}
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if cfg.MaxURLs > 0 {
+		maxURLs = cfg.MaxURLs
+	}
+	resolver = New(NewPublicClient(timeout), Options{
+		MaxHops:  cfg.MaxHops,
+		Timeout:  timeout,
+		CacheTTL: time.Duration(cfg.CacheTTLMinutes) * time.Minute,
+	})
+}
+
+// Enabled reports whether links are resolved
+func Enabled() bool {
+	return resolver != nil
+}
+
+// MaxURLs returns how many links of a message are resolved at most
+func MaxURLs() int {
+	return maxURLs
+}
+
+// Resolve returns the redirect chain of link with the shared resolver
+func Resolve(ctx context.Context, link string) (Resolution, error) {
+	if resolver == nil {
+		return Resolution{Chain: []string{link}}, errors.New("URL resolution is disabled")
+	}
+	return resolver.Resolve(ctx, link)
+}
+
+// NewPublicClient returns an HTTP client that only connects to public addresses,
+// so links posted in groups cannot make the bot reach hosts on its own network
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
@@ -16,6 +16,39 @@ CREATE TABLE IF NOT EXISTS `group_infos` (
   `ban_bio_link` tinyint(1) DEFAULT 1,
   `enable_cas` tinyint(1) DEFAULT 1,
   `enable_aicheck` tinyint(1) DEFAULT 0,
+  `restrict_score` int(11) DEFAULT 30,
+  `ban_score` int(11) DEFAULT 100,
+  `emoji_min_count` int(11) DEFAULT 2,
+  `emoji_ratio` int(11) DEFAULT 0,
+  `random_username_threshold` int(11) DEFAULT 70,
+  `min_account_age_days` int(11) DEFAULT 0,
+  `account_age_action` varchar(16) DEFAULT 'restrict',
+  `ban_avatar_hash` tinyint(1) DEFAULT 1,
+  `ban_no_avatar` tinyint(1) DEFAULT 0,
+  `ban_impersonation` tinyint(1) DEFAULT 1,
+  `ban_invisible_chars` tinyint(1) DEFAULT 1,
+  `blocklist_providers` varchar(255) DEFAULT NULL,
+  `enable_message_scan` tinyint(1) DEFAULT 1,
+  `message_provider_check` tinyint(1) DEFAULT 1,
+  `message_action` varchar(16) DEFAULT 'restrict',
+  `enable_bayes` tinyint(1) DEFAULT 1,
+  `ai_policy` text,
+  `enable_ai_profile` tinyint(1) DEFAULT 0,
+  `flood_messages` int(11) DEFAULT 0,
+  `flood_media` int(11) DEFAULT 0,
+  `flood_mentions` int(11) DEFAULT 0,
+  `flood_window_sec` int(11) DEFAULT 10,
+  `flood_action` varchar(16) DEFAULT 'mute',
+  `flood_mute_minutes` int(11) DEFAULT 10,
+  `duplicate_groups` int(11) DEFAULT 0,
+  `duplicate_users` int(11) DEFAULT 0,
+  `enable_edit_scan` tinyint(1) DEFAULT 1,
+  `edit_window_minutes` int(11) DEFAULT 0,
+  `block_inline_bots` tinyint(1) DEFAULT 0,
+  `forward_channel_policy` varchar(16) DEFAULT 'allow',
+  `forward_channel_action` varchar(16) DEFAULT 'delete',
+  `block_hidden_forwards` tinyint(1) DEFAULT 0,
+  `hidden_forward_action` varchar(16) DEFAULT 'delete',
   `created_at` timestamp NULL DEFAULT NULL,
   `updated_at` timestamp NULL DEFAULT NULL,
   PRIMARY KEY (`id`),
@@ -29,6 +62,13 @@ CREATE TABLE IF NOT EXISTS `ban_records` (
   `group_id` bigint(20) NOT NULL,
   `user_id` bigint(20) NOT NULL,
   `reason` text NOT NULL,
+  `action` varchar(16) DEFAULT 'restrict',
+  `score` int(11) DEFAULT 0,
+  `score_detail` text,
+  `account_created_at` timestamp NULL DEFAULT NULL,
+  `message_text` text,
+  `feedback` varchar(16) DEFAULT '',
+  `edited` tinyint(1) DEFAULT 0,
   `is_unbanned` tinyint(1) DEFAULT 0,
   `unbanned_by` varchar(255) DEFAULT '',
   `created_at` timestamp NULL DEFAULT NULL,
@@ -49,4 +89,63 @@ CREATE TABLE IF NOT EXISTS `pending_messages` (
   PRIMARY KEY (`id`),
   UNIQUE KEY `idx_user_chat` (`user_id`, `chat_id`),
   UNIQUE KEY `idx_chat_message` (`chat_id`, `message_id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
\ No newline at end of file
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create BlocklistPattern table
+CREATE TABLE IF NOT EXISTS `blocklist_patterns` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `group_id` bigint(20) NOT NULL,
+  `field` varchar(32) NOT NULL,
+  `match_type` varchar(16) NOT NULL,
+  `action` varchar(16) NOT NULL,
+  `pattern` varchar(255) NOT NULL,
+  `created_by` bigint(20) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_group_id` (`group_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create AvatarHash table
+CREATE TABLE IF NOT EXISTS `avatar_hashes` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `group_id` bigint(20) NOT NULL,
+  `hash` bigint(20) unsigned NOT NULL,
+  `source_user_id` bigint(20) DEFAULT NULL,
+  `added_by` varchar(32) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_group_id` (`group_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create GroupListEntry table
+CREATE TABLE IF NOT EXISTS `group_list_entries` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `group_id` bigint(20) NOT NULL,
+  `list` varchar(32) NOT NULL,
+  `value` varchar(255) NOT NULL,
+  `created_by` bigint(20) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_group_list_value` (`group_id`, `list`, `value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create ReputationEntry table
+CREATE TABLE IF NOT EXISTS `reputation_entries` (
+  `user_id` bigint(20) NOT NULL,
+  `provider` varchar(64) NOT NULL,
+  `listed` tinyint(1) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`user_id`, `provider`),
+  KEY `idx_reputation_entries_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create BayesToken table
+CREATE TABLE IF NOT EXISTS `bayes_tokens` (
+  `token` varchar(191) NOT NULL,
+  `spam` bigint(20) NOT NULL DEFAULT 0,
+  `ham` bigint(20) NOT NULL DEFAULT 0,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`token`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;